@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/gnolang/supernova/internal"
+	"github.com/peterbourgon/ff/v3/ffcli"
+)
+
+var errMissingPath = errors.New("missing transaction file path")
+
+// newExportCmd creates the transaction export subcommand
+func newExportCmd() *ffcli.Command {
+	var (
+		cfg = &internal.Config{}
+		fs  = flag.NewFlagSet("export", flag.ExitOnError)
+
+		scenarioPath string
+	)
+
+	// Register the flags
+	registerFlags(fs, cfg)
+
+	fs.StringVar(
+		&scenarioPath,
+		"config",
+		"",
+		"the path to the YAML scenario file. Explicitly set flags override the scenario values",
+	)
+
+	return &ffcli.Command{
+		Name:       "export",
+		ShortUsage: "export [flags] <path>",
+		ShortHelp:  "Exports the signed run transactions to a file, without sending them out",
+		LongHelp: "Prepares the run (predeploying any pending transactions, and funding the sub-accounts), " +
+			"and writes the constructed and signed run transactions, along with their metadata, to the given file. " +
+			"The exported transactions are sent out using the replay subcommand",
+		FlagSet: fs,
+		Exec: func(ctx context.Context, args []string) error {
+			if len(args) != 1 {
+				return errMissingPath
+			}
+
+			if scenarioPath != "" {
+				if err := loadScenario(fs, cfg, scenarioPath); err != nil {
+					return err
+				}
+			}
+
+			return execExport(ctx, cfg, args[0])
+		},
+	}
+}
+
+// execExport starts the transaction export workflow
+func execExport(ctx context.Context, cfg *internal.Config, path string) error {
+	// Validate the configuration
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration, %w", err)
+	}
+
+	// Create the pipeline, and export the transactions
+	pipeline, err := internal.NewPipeline(cfg)
+	if err != nil {
+		return fmt.Errorf("unable to create pipeline, %w", err)
+	}
+
+	return pipeline.Export(ctx, path)
+}
@@ -0,0 +1,23 @@
+package main
+
+import "strings"
+
+// repeatedFlag is a flag value that can be repeated,
+// with a single list value per flag occurrence
+type repeatedFlag struct {
+	values *[]string
+}
+
+func (r repeatedFlag) String() string {
+	if r.values == nil {
+		return ""
+	}
+
+	return strings.Join(*r.values, " ")
+}
+
+func (r repeatedFlag) Set(value string) error {
+	*r.values = append(*r.values, value)
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/gnolang/supernova/internal"
+	"github.com/peterbourgon/ff/v3/ffcli"
+)
+
+// newReclaimCmd creates the funds reclamation subcommand
+func newReclaimCmd() *ffcli.Command {
+	var (
+		cfg = &internal.Config{}
+		fs  = flag.NewFlagSet("reclaim", flag.ExitOnError)
+	)
+
+	// Register the flags
+	registerAccountFlags(fs, cfg)
+
+	return &ffcli.Command{
+		Name:       "reclaim",
+		ShortUsage: "reclaim [flags]",
+		ShortHelp:  "Sweeps the sub-account funds back to the distributor",
+		LongHelp: "Derives the sub-accounts from the mnemonic, and sends their remaining balances " +
+			"(minus fees) back to the distributor (account 0 in the mnemonic)",
+		FlagSet: fs,
+		Exec: func(ctx context.Context, _ []string) error {
+			return execReclaim(ctx, cfg)
+		},
+	}
+}
+
+// execReclaim starts the funds reclamation workflow
+func execReclaim(ctx context.Context, cfg *internal.Config) error {
+	// Validate the configuration
+	if err := cfg.ValidateAccounts(); err != nil {
+		return fmt.Errorf("invalid configuration, %w", err)
+	}
+
+	// Create the pipeline, and reclaim the funds
+	pipeline, err := internal.NewPipeline(cfg)
+	if err != nil {
+		return fmt.Errorf("unable to create pipeline, %w", err)
+	}
+
+	return pipeline.Reclaim(ctx)
+}
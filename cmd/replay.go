@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/gnolang/supernova/internal"
+	"github.com/peterbourgon/ff/v3/ffcli"
+)
+
+// newReplayCmd creates the transaction replay subcommand
+func newReplayCmd() *ffcli.Command {
+	var (
+		cfg = &internal.Config{}
+		fs  = flag.NewFlagSet("replay", flag.ExitOnError)
+	)
+
+	// Register the flags
+	registerURLFlag(fs, cfg)
+	registerSendFlags(fs, cfg)
+
+	return &ffcli.Command{
+		Name:       "replay",
+		ShortUsage: "replay [flags] <path>",
+		ShortHelp:  "Sends out the transactions exported to a file, and collects the results",
+		LongHelp: "Loads the signed transactions exported using the export subcommand, and sends them out " +
+			"the same way the exported run would have (in a single burst, or following the load profile). " +
+			"The signer accounts on the node need to match the exported account numbers and sequences",
+		FlagSet: fs,
+		Exec: func(ctx context.Context, args []string) error {
+			if len(args) != 1 {
+				return errMissingPath
+			}
+
+			return execReplay(ctx, cfg, args[0])
+		},
+	}
+}
+
+// execReplay starts the transaction replay workflow
+func execReplay(ctx context.Context, cfg *internal.Config, path string) error {
+	// Validate the configuration
+	if err := cfg.ValidateReplay(); err != nil {
+		return fmt.Errorf("invalid configuration, %w", err)
+	}
+
+	// Create the pipeline, and replay the transactions
+	pipeline, err := internal.NewPipeline(cfg)
+	if err != nil {
+		return fmt.Errorf("unable to create pipeline, %w", err)
+	}
+
+	return pipeline.Replay(ctx, path)
+}
@@ -5,8 +5,12 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gnolang/supernova/internal"
+	"github.com/gnolang/supernova/internal/query"
 	"github.com/gnolang/supernova/internal/runtime"
 	"github.com/peterbourgon/ff/v3/ffcli"
 )
@@ -15,72 +19,281 @@ func main() {
 	var (
 		cfg = &internal.Config{}
 		fs  = flag.NewFlagSet("pipeline", flag.ExitOnError)
+
+		scenarioPath string
 	)
 
 	// Register the flags
 	registerFlags(fs, cfg)
 
+	fs.StringVar(
+		&scenarioPath,
+		"config",
+		"",
+		"the path to the YAML scenario file. Explicitly set flags override the scenario values",
+	)
+
 	cmd := &ffcli.Command{
 		ShortUsage: "[flags] [<arg>...]",
 		LongHelp:   "Starts the stress testing suite against a Gno TM2 cluster",
 		FlagSet:    fs,
-		Exec: func(_ context.Context, _ []string) error {
-			return execMain(cfg)
+		Subcommands: []*ffcli.Command{
+			newReclaimCmd(),
+			newExportCmd(),
+			newReplayCmd(),
+		},
+		Exec: func(ctx context.Context, _ []string) error {
+			if scenarioPath != "" {
+				if err := loadScenario(fs, cfg, scenarioPath); err != nil {
+					return err
+				}
+			}
+
+			return execMain(ctx, cfg)
 		},
 	}
 
-	if err := cmd.ParseAndRun(context.Background(), os.Args[1:]); err != nil {
+	// Interrupting the run stops it cleanly,
+	// and the results gathered so far are still handled
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	if err := cmd.ParseAndRun(ctx, os.Args[1:]); err != nil {
+		stop()
+
 		_, _ = fmt.Fprintf(os.Stderr, "%+v", err)
 
 		os.Exit(1)
 	}
+
+	stop()
 }
 
 // registerFlags registers the main configuration flags
 func registerFlags(fs *flag.FlagSet, c *internal.Config) {
+	registerAccountFlags(fs, c)
+	registerSendFlags(fs, c)
+
 	fs.StringVar(
-		&c.URL,
-		"url",
+		&c.Mode,
+		"mode",
+		runtime.RealmDeployment.String(),
+		fmt.Sprintf(
+			"the mode for the stress test. Possible modes: [%s, %s, %s, %s, %s, %s, %s, %s, %s]",
+			runtime.RealmDeployment.String(), runtime.PackageDeployment.String(), runtime.RealmCall.String(),
+			runtime.RealmStorage.String(), runtime.BankSend.String(), runtime.Run.String(), runtime.Mixed.String(),
+			runtime.History.String(), internal.QueryMode,
+		),
+	)
+
+	fs.StringVar(
+		&c.Mix,
+		"mix",
 		"",
-		"the JSON-RPC URL of the cluster",
+		fmt.Sprintf(
+			"the weighted runtime mix of the %s mode, as a comma separated list (ex. %s:70,%s:30)",
+			runtime.Mixed.String(), runtime.RealmCall.String(), runtime.PackageDeployment.String(),
+		),
 	)
 
 	fs.StringVar(
-		&c.ChainID,
-		"chain-id",
-		"dev",
-		"the chain ID of the Gno blockchain",
+		&c.Realm,
+		"realm",
+		"",
+		fmt.Sprintf(
+			"the path to the custom realm, either a single .gno file or a directory of .gno files "+
+				"(and an optional gnomod.toml), used by the %s and %s modes",
+			runtime.RealmDeployment.String(), runtime.RealmCall.String(),
+		),
 	)
 
 	fs.StringVar(
-		&c.Mnemonic,
-		"mnemonic",
+		&c.Script,
+		"script",
 		"",
-		"the mnemonic used to generate sub-accounts",
+		fmt.Sprintf(
+			"the path to the custom script executed by the %s mode, either a single .gno file or a directory "+
+				"of .gno files, in the main package. If unset, the built-in script is executed",
+			runtime.Run.String(),
+		),
 	)
 
 	fs.StringVar(
-		&c.Mode,
-		"mode",
-		runtime.RealmDeployment.String(),
+		&c.RealmPath,
+		"realm-path",
+		"",
 		fmt.Sprintf(
-			"the mode for the stress test. Possible modes: [%s, %s, %s]",
-			runtime.RealmDeployment.String(), runtime.PackageDeployment.String(), runtime.RealmCall.String(),
+			"the path of an existing on-chain realm called by the %s mode (ex. gno.land/r/demo/counter). "+
+				"If set, no realm is deployed",
+			runtime.RealmCall.String(),
 		),
 	)
 
 	fs.StringVar(
-		&c.Output,
-		"output",
+		&c.RealmFunc,
+		"realm-func",
 		"",
-		"the output path for the results JSON",
+		fmt.Sprintf(
+			"the realm function called by the %s mode. If unset, SayHello of the built-in realm is called",
+			runtime.RealmCall.String(),
+		),
+	)
+
+	fs.Var(
+		repeatedFlag{values: &c.RealmArgs},
+		"realm-arg",
+		"the argument template of the called realm function, rendered for each tx (ex. key-{{.Index}}). "+
+			"Repeated for each argument, in order",
+	)
+
+	fs.StringVar(
+		&c.TransferPattern,
+		"transfer-pattern",
+		string(runtime.RingTransfer),
+		fmt.Sprintf(
+			"the pattern of the %s transfers between sub-accounts. Possible patterns: [%s, %s, %s]",
+			runtime.BankSend.String(), runtime.RingTransfer, runtime.RandomTransfer, runtime.FanInTransfer,
+		),
 	)
 
 	fs.Uint64Var(
-		&c.SubAccounts,
-		"sub-accounts",
+		&c.TransferAmount,
+		"transfer-amount",
+		1,
+		fmt.Sprintf("the amount (ugnot) of a single %s transfer", runtime.BankSend.String()),
+	)
+
+	fs.StringVar(
+		&c.PayloadSize,
+		"payload-size",
+		"",
+		fmt.Sprintf(
+			"the size (bytes) of the payload generated for each %s and %s tx, "+
+				"or a sweep across a size range, as <from>:<to>:<steps> (ex. 1024:65536:8)",
+			runtime.PackageDeployment.String(), runtime.RealmDeployment.String(),
+		),
+	)
+
+	fs.Uint64Var(
+		&c.PayloadFiles,
+		"payload-files",
+		1,
+		"the number of files the generated payload is split into",
+	)
+
+	fs.Uint64Var(
+		&c.StorageKeys,
+		"storage-keys",
+		10_000,
+		fmt.Sprintf("the size of the %s key space", runtime.RealmStorage.String()),
+	)
+
+	fs.Uint64Var(
+		&c.StorageValueSize,
+		"storage-value-size",
+		256,
+		fmt.Sprintf("the size (bytes) of the %s values", runtime.RealmStorage.String()),
+	)
+
+	fs.Uint64Var(
+		&c.StorageDeletes,
+		"storage-deletes",
 		10,
-		"the number of sub-accounts that will send out transactions",
+		fmt.Sprintf("the share (percentage) of %s calls that delete a key", runtime.RealmStorage.String()),
+	)
+
+	fs.Uint64Var(
+		&c.StoragePrice,
+		"storage-price",
+		runtime.DefaultStoragePrice,
+		"the storage deposit price (ugnot per byte) of the chain, for funding the accounts",
+	)
+
+	fs.StringVar(
+		&c.QueryPath,
+		"query-path",
+		query.RenderPath,
+		fmt.Sprintf("the ABCI path of the query load. Possible paths: [%s, %s]", query.RenderPath, query.EvalPath),
+	)
+
+	fs.StringVar(
+		&c.QueryRealm,
+		"query-realm",
+		"",
+		fmt.Sprintf(
+			"the path of the queried realm (ex. gno.land/r/demo/counter). Required by the %s mode, "+
+				"otherwise the realm deployed by the run is queried",
+			internal.QueryMode,
+		),
+	)
+
+	fs.StringVar(
+		&c.QueryExpr,
+		"query-expr",
+		"",
+		fmt.Sprintf(
+			"the query template, rendered for each query: the render path for %s, "+
+				"or the evaluated expression for %s (default Render(\"\"))",
+			query.RenderPath, query.EvalPath,
+		),
+	)
+
+	fs.Uint64Var(
+		&c.QueryConcurrency,
+		"query-concurrency",
+		0,
+		fmt.Sprintf(
+			"the number of concurrent query workers running alongside the transactions "+
+				"(at least one in the %s mode). If unset, no queries are sent",
+			internal.QueryMode,
+		),
+	)
+
+	fs.Uint64Var(
+		&c.QueryRate,
+		"query-rate",
+		0,
+		"the total query rate cap (queries / s). If unset, queries are sent out as fast as possible",
+	)
+
+	fs.StringVar(
+		&c.HistoryURL,
+		"history-url",
+		"",
+		fmt.Sprintf(
+			"the JSON-RPC URL of the source chain whose blocks are replayed by the %s mode. "+
+				"If unset, the blocks of the cluster itself are replayed",
+			runtime.History.String(),
+		),
+	)
+
+	fs.StringVar(
+		&c.HistoryBlocks,
+		"history-blocks",
+		"",
+		fmt.Sprintf(
+			"the source chain block range replayed by the %s mode, as <from>:<to> (ex. 1000:2000)",
+			runtime.History.String(),
+		),
+	)
+
+	fs.StringVar(
+		&c.HistoryFile,
+		"history-file",
+		"",
+		fmt.Sprintf(
+			"the path to the tx export file replayed by the %s mode, instead of a source chain block range",
+			runtime.History.String(),
+		),
+	)
+
+	fs.Float64Var(
+		&c.HistorySpeed,
+		"history-speed",
+		1,
+		fmt.Sprintf(
+			"the speed factor of the %s replay, applied to the relative source timing (ex. 2 replays twice as fast)",
+			runtime.History.String(),
+		),
 	)
 
 	fs.Uint64Var(
@@ -90,16 +303,240 @@ func registerFlags(fs *flag.FlagSet, c *internal.Config) {
 		"the total number of transactions to be emitted",
 	)
 
+	fs.Uint64Var(
+		&c.MsgsPerTx,
+		"msgs-per-tx",
+		1,
+		"the number of messages in a single transaction. Gas estimation and fees are based on the whole transaction",
+	)
+
+	fs.Uint64Var(
+		&c.FundingMargin,
+		"funding-margin",
+		20,
+		"the safety margin (percentage) added to the estimated sub-account cost (fees and transferred value)",
+	)
+
+	fs.Uint64Var(
+		&c.Rate,
+		"rate",
+		0,
+		"the target send rate (txs / s). If unset, transactions are sent out in a single burst",
+	)
+
+	fs.DurationVar(
+		&c.Duration,
+		"duration",
+		0,
+		"the duration of a time-bounded run, at the specified -rate. Overrides -transactions",
+	)
+
+	fs.StringVar(
+		&c.Profile,
+		"profile",
+		"",
+		"the load profile, as a comma separated list of phases (ex. ramp:10:200:1m,steps:50:200:50:30s,spike:1000:5s). "+
+			"Overrides -rate and -duration",
+	)
+
+	fs.BoolVar(
+		&c.DryRun,
+		"dry-run",
+		false,
+		"simulates, constructs and signs the run transactions, and displays the execution plan "+
+			"(funding, gas, blocks and duration) without broadcasting anything",
+	)
+}
+
+// registerSendFlags registers the batching, send concurrency
+// and result collection flags, shared by the stress test and the replay
+func registerSendFlags(fs *flag.FlagSet, c *internal.Config) {
+	fs.StringVar(
+		&c.Output,
+		"output",
+		"",
+		"the output path for the results JSON",
+	)
+
 	fs.Uint64Var(
 		&c.BatchSize,
 		"batch",
 		100,
 		"the batch size of JSON-RPC transactions",
 	)
+
+	fs.Uint64Var(
+		&c.Concurrency,
+		"concurrency",
+		1,
+		"the number of concurrent send lanes (connections). Transactions are partitioned into lanes by sub-account",
+	)
+
+	fs.DurationVar(
+		&c.PollInterval,
+		"poll-interval",
+		2*time.Second,
+		"the interval in which the collector polls the node for new blocks",
+	)
+
+	fs.DurationVar(
+		&c.CollectTimeout,
+		"collect-timeout",
+		5*time.Minute,
+		"the maximum time spent collecting results once all transactions are sent out. "+
+			"Uncommitted transactions are reported as not included",
+	)
+
+	fs.Uint64Var(
+		&c.IdleBlocks,
+		"idle-blocks",
+		0,
+		"the number of consecutive blocks without run transactions after which the collection ends early. "+
+			"If unset, the collection only ends once it times out",
+	)
+}
+
+// registerAccountFlags registers the cluster and account flags,
+// shared by the stress test and the funds reclamation
+func registerAccountFlags(fs *flag.FlagSet, c *internal.Config) {
+	registerURLFlag(fs, c)
+
+	fs.StringVar(
+		&c.ChainID,
+		"chain-id",
+		"dev",
+		"the chain ID of the Gno blockchain",
+	)
+
+	fs.StringVar(
+		&c.Mnemonic,
+		"mnemonic",
+		"",
+		"the mnemonic used to generate sub-accounts",
+	)
+
+	fs.Uint64Var(
+		&c.SubAccounts,
+		"sub-accounts",
+		10,
+		"the number of sub-accounts that will send out transactions",
+	)
+}
+
+// registerURLFlag registers the cluster URL flag
+func registerURLFlag(fs *flag.FlagSet, c *internal.Config) {
+	fs.StringVar(
+		&c.URL,
+		"url",
+		"",
+		"the JSON-RPC URL of the cluster, or a comma separated list of endpoint URLs (the first one is the primary)",
+	)
+}
+
+// loadScenario loads the scenario file into the configuration.
+// Flags that were explicitly set override the scenario values,
+// including the values of the scenario stages
+func loadScenario(fs *flag.FlagSet, cfg *internal.Config, path string) error {
+	var (
+		setFlags = make(map[string]string)
+
+		// The repeated flag values can't be set again from their
+		// string value, so they are restored as they were parsed
+		realmArgs = cfg.RealmArgs
+	)
+
+	fs.Visit(func(f *flag.Flag) {
+		setFlags[f.Name] = f.Value.String()
+	})
+
+	if err := internal.LoadScenario(path, cfg); err != nil {
+		return fmt.Errorf("unable to load scenario, %w", err)
+	}
+
+	for name, value := range setFlags {
+		if name == "realm-arg" {
+			cfg.RealmArgs = realmArgs
+
+			continue
+		}
+
+		if err := fs.Set(name, value); err != nil {
+			return fmt.Errorf("unable to override scenario value %s, %w", name, err)
+		}
+	}
+
+	for index := range cfg.Stages {
+		stage := &cfg.Stages[index]
+
+		for name := range setFlags {
+			switch name {
+			case "mode":
+				stage.Mode = cfg.Mode
+			case "mix":
+				stage.Mix = cfg.Mix
+			case "realm":
+				stage.Realm = cfg.Realm
+			case "script":
+				stage.Script = cfg.Script
+			case "realm-path":
+				stage.RealmPath = cfg.RealmPath
+			case "realm-func":
+				stage.RealmFunc = cfg.RealmFunc
+			case "realm-arg":
+				stage.RealmArgs = cfg.RealmArgs
+			case "transfer-pattern":
+				stage.TransferPattern = cfg.TransferPattern
+			case "transfer-amount":
+				stage.TransferAmount = cfg.TransferAmount
+			case "payload-size":
+				stage.PayloadSize = cfg.PayloadSize
+			case "payload-files":
+				stage.PayloadFiles = cfg.PayloadFiles
+			case "storage-keys":
+				stage.StorageKeys = cfg.StorageKeys
+			case "storage-value-size":
+				stage.StorageValueSize = cfg.StorageValueSize
+			case "storage-deletes":
+				stage.StorageDeletes = cfg.StorageDeletes
+			case "storage-price":
+				stage.StoragePrice = cfg.StoragePrice
+			case "query-path":
+				stage.QueryPath = cfg.QueryPath
+			case "query-realm":
+				stage.QueryRealm = cfg.QueryRealm
+			case "query-expr":
+				stage.QueryExpr = cfg.QueryExpr
+			case "query-concurrency":
+				stage.QueryConcurrency = cfg.QueryConcurrency
+			case "query-rate":
+				stage.QueryRate = cfg.QueryRate
+			case "history-url":
+				stage.HistoryURL = cfg.HistoryURL
+			case "history-blocks":
+				stage.HistoryBlocks = cfg.HistoryBlocks
+			case "history-file":
+				stage.HistoryFile = cfg.HistoryFile
+			case "history-speed":
+				stage.HistorySpeed = cfg.HistorySpeed
+			case "transactions":
+				stage.Transactions = cfg.Transactions
+			case "msgs-per-tx":
+				stage.MsgsPerTx = cfg.MsgsPerTx
+			case "rate":
+				stage.Rate = cfg.Rate
+			case "duration":
+				stage.Duration = cfg.Duration
+			case "profile":
+				stage.Profile = cfg.Profile
+			}
+		}
+	}
+
+	return nil
 }
 
 // execMain starts the stress test workflow (runs the pipeline)
-func execMain(cfg *internal.Config) error {
+func execMain(ctx context.Context, cfg *internal.Config) error {
 	// Validate the configuration
 	if err := cfg.Validate(); err != nil {
 		return fmt.Errorf("invalid configuration, %w", err)
@@ -111,5 +548,5 @@ func execMain(cfg *internal.Config) error {
 		return fmt.Errorf("unable to create pipeline, %w", err)
 	}
 
-	return pipeline.Execute(context.Background())
+	return pipeline.Execute(ctx)
 }
@@ -5,32 +5,62 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"time"
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	core_types "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	bfttypes "github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/std"
 	"github.com/gnolang/supernova/internal/common"
 	"github.com/schollz/progressbar/v3"
 )
 
+// sendInterval is the interval in which the stream
+// checks if new transactions are due to be sent out
+const sendInterval = 100 * time.Millisecond
+
 // Batcher batches signed transactions
 // to the Gno Tendermint node
 type Batcher struct {
-	cli Client
-	ctx context.Context
+	cli   Client
+	lanes []Lane
+	ctx   context.Context
+
+	// The send lane of each signer account, kept for the whole run,
+	// so consecutive account transactions always go to the same endpoint
+	accountLanes map[string]int
 }
 
-// NewBatcher creates a new Batcher instance
-func NewBatcher(ctx context.Context, cli Client) *Batcher {
+// sentBatch is a batch that was sent out
+type sentBatch struct {
+	sentAt   time.Time // the time the batch was sent out
+	endpoint string    // the endpoint the batch was sent to
+	results  []any     // the batch results
+}
+
+// NewBatcher creates a new Batcher instance.
+// Transactions are sent out concurrently over the lanes,
+// or over the main client if no lanes are specified
+func NewBatcher(ctx context.Context, cli Client, lanes ...Lane) *Batcher {
+	if len(lanes) == 0 {
+		lanes = []Lane{{Client: cli}}
+	}
+
 	return &Batcher{
-		cli: cli,
-		ctx: ctx,
+		cli:          cli,
+		lanes:        lanes,
+		ctx:          ctx,
+		accountLanes: make(map[string]int),
 	}
 }
 
 // BatchTransactions batches provided transactions using the
-// specified batch size
-func (b *Batcher) BatchTransactions(txs []*std.Tx, batchSize int) (*TxBatchResult, error) {
+// specified batch size. The sent transactions carry the given labels, if any
+func (b *Batcher) BatchTransactions(
+	txs []*std.Tx,
+	labels []common.TxLabel,
+	batchSize int,
+) (*TxBatchResult, error) {
 	fmt.Printf("\n📦 Batching Transactions 📦\n\n")
 
 	// Note the current latest block
@@ -49,30 +79,41 @@ func (b *Batcher) BatchTransactions(txs []*std.Tx, batchSize int) (*TxBatchResul
 		return nil, fmt.Errorf("unable to batch transactions, %w", err)
 	}
 
+	// Partition the transactions into send lanes
+	lanes := b.partitionTransactions(txs, preparedTxs)
+
 	// Generate the batches
-	readyBatches, err := b.generateBatches(preparedTxs, batchSize)
+	readyBatches, err := b.generateBatches(lanes, batchSize)
 	if err != nil {
 		return nil, fmt.Errorf("unable to generate batches, %w", err)
 	}
 
 	// Execute the batch requests.
-	// Batch requests need to be sent out sequentially
-	// to preserve account sequence order
-	batchResults, err := sendBatches(readyBatches)
+	// Batch requests within a lane need to be sent out sequentially
+	// to preserve account sequence order, while the lanes are sent out concurrently.
+	// If the batching is interrupted, only the batches sent out so far are parsed
+	sentBatches, err := b.sendBatches(readyBatches)
 	if err != nil {
 		return nil, fmt.Errorf("unable to send batches, %w", err)
 	}
 
 	// Parse the results
-	txHashes, err := parseBatchResults(batchResults, len(txs))
+	sentTxs, err := parseBatchResults(sentBatches)
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse batch results, %w", err)
 	}
 
-	fmt.Printf("✅ Successfully sent %d txs in %d batches\n", len(txs), len(readyBatches))
+	// Note down the transaction labels
+	hashLabels := txLabels(txs, labels, preparedTxs)
+
+	for index := range sentTxs {
+		hashLabels[string(sentTxs[index].Hash)].apply(&sentTxs[index])
+	}
+
+	fmt.Printf("✅ Successfully sent %d txs in %d batches\n", len(sentTxs), len(sentBatches))
 
 	return &TxBatchResult{
-		TxHashes:   txHashes,
+		SentTxs:    sentTxs,
 		StartBlock: latest,
 	}, nil
 }
@@ -96,69 +137,112 @@ func prepareTransactions(txs []*std.Tx) ([][]byte, error) {
 	return marshalledTxs, nil
 }
 
-// generateBatches generates batches of transactions
-func (b *Batcher) generateBatches(txs [][]byte, batchSize int) ([]common.Batch, error) {
+// generateBatches generates batches of transactions for each send lane
+func (b *Batcher) generateBatches(lanes [][][]byte, batchSize int) ([][]common.Batch, error) {
 	var (
-		batches      = generateBatches(txs, batchSize)
-		numBatches   = len(batches)
-		readyBatches = make([]common.Batch, numBatches)
+		laneBatches  = generateLaneBatches(lanes, batchSize)
+		numBatches   = countBatches(laneBatches)
+		readyBatches = make([][]common.Batch, len(laneBatches))
 	)
 
 	fmt.Printf("\nGenerating batches...\n")
 
 	bar := progressbar.Default(int64(numBatches), "batches generated")
 
-	for index, batch := range batches {
-		cliBatch := b.cli.CreateBatch()
+	for lane, batches := range laneBatches {
+		readyBatches[lane] = make([]common.Batch, 0, len(batches))
+
+		for _, batch := range batches {
+			cliBatch := b.lanes[lane].Client.CreateBatch()
 
-		for _, tx := range batch {
-			// Append the transaction
-			if err := cliBatch.AddTxBroadcast(tx); err != nil {
-				return nil, fmt.Errorf("unable to prepare transaction, %w", err)
+			for _, tx := range batch {
+				// Append the transaction
+				if err := cliBatch.AddTxBroadcast(tx); err != nil {
+					return nil, fmt.Errorf("unable to prepare transaction, %w", err)
+				}
 			}
-		}
 
-		readyBatches[index] = cliBatch
+			readyBatches[lane] = append(readyBatches[lane], cliBatch)
 
-		_ = bar.Add(1) //nolint:errcheck // No need to check
+			_ = bar.Add(1) //nolint:errcheck // No need to check
+		}
 	}
 
 	return readyBatches, nil
 }
 
-// sendBatches sends the prepared batch requests
-func sendBatches(readyBatches []common.Batch) ([][]any, error) {
+// sendBatches sends the prepared batch requests of each lane,
+// and notes down the time and endpoint each batch was sent out to.
+// Once the context is canceled, no further batches are sent out
+func (b *Batcher) sendBatches(readyBatches [][]common.Batch) ([]sentBatch, error) {
 	var (
-		numBatches   = len(readyBatches)
-		batchResults = make([][]any, numBatches)
+		numBatches      = countBatches(readyBatches)
+		laneSentBatches = make([][]sentBatch, len(readyBatches))
 	)
 
 	fmt.Printf("\nSending batches...\n")
 
 	bar := progressbar.Default(int64(numBatches), "batches sent")
 
-	for index, readyBatch := range readyBatches {
-		batchResult, err := readyBatch.Execute()
-		if err != nil {
-			return nil, fmt.Errorf("unable to batch request, %w", err)
+	err := runLanes(len(readyBatches), func(lane int) error {
+		for _, readyBatch := range readyBatches[lane] {
+			if b.ctx.Err() != nil {
+				return nil
+			}
+
+			sendTime := time.Now()
+
+			batchResult, err := readyBatch.Execute()
+			if err != nil {
+				return fmt.Errorf("unable to batch request, %w", err)
+			}
+
+			laneSentBatches[lane] = append(laneSentBatches[lane], sentBatch{
+				sentAt:   sendTime,
+				endpoint: b.lanes[lane].Endpoint,
+				results:  batchResult,
+			})
+
+			_ = bar.Add(1) //nolint:errcheck // No need to check
 		}
 
-		batchResults[index] = batchResult
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		_ = bar.Add(1) //nolint:errcheck // No need to check
+	// Merge the lane results
+	sentBatches := make([]sentBatch, 0, numBatches)
+
+	for _, batches := range laneSentBatches {
+		sentBatches = append(sentBatches, batches...)
+	}
+
+	if len(sentBatches) < numBatches {
+		fmt.Printf("⚠️ Batching interrupted, %d / %d batches were sent\n", len(sentBatches), numBatches)
+
+		return sentBatches, nil
 	}
 
 	fmt.Printf("✅ Successfully sent %d batches\n", numBatches)
 
-	return batchResults, nil
+	return sentBatches, nil
 }
 
-// parseBatchResults extracts transaction hashes
-// from batch results
-func parseBatchResults(batchResults [][]any, numTx int) ([][]byte, error) {
+// parseBatchResults extracts the sent transactions
+// from batch results. Transactions rejected by the node
+// are marked as such, and don't fail the run
+func parseBatchResults(sentBatches []sentBatch) ([]common.SentTx, error) {
+	numTx := 0
+	for _, batch := range sentBatches {
+		numTx += len(batch.results)
+	}
+
 	var (
-		txHashes = make([][]byte, numTx)
+		sentTxs  = make([]common.SentTx, numTx)
 		index    = 0
+		rejected = 0
 	)
 
 	fmt.Printf("\nParsing batch results...\n")
@@ -167,9 +251,9 @@ func parseBatchResults(batchResults [][]any, numTx int) ([][]byte, error) {
 
 	// Parsing is done in a separate loop to not hinder
 	// the batch send speed (as txs need to be parsed sequentially)
-	for _, batchResult := range batchResults {
+	for _, batch := range sentBatches {
 		// For each batch, extract the transaction hashes
-		for _, txResultRaw := range batchResult {
+		for _, txResultRaw := range batch.results {
 			txResult, ok := txResultRaw.(*core_types.ResultBroadcastTx)
 			if !ok {
 				return nil, errors.New("invalid result type returned")
@@ -177,23 +261,28 @@ func parseBatchResults(batchResults [][]any, numTx int) ([][]byte, error) {
 
 			// Check the errors
 			if txResult.Error != nil {
-				return nil, fmt.Errorf(
-					"error when parsing transaction %s, %w",
-					txResult.Hash,
-					txResult.Error,
-				)
+				rejected++
 			}
 
-			txHashes[index] = txResult.Hash
+			sentTxs[index] = common.SentTx{
+				Hash:     txResult.Hash,
+				SentAt:   batch.sentAt,
+				Err:      txResult.Error,
+				Endpoint: batch.endpoint,
+			}
 			index++
 
 			_ = bar.Add(1) //nolint:errcheck // No need to check
 		}
 	}
 
-	fmt.Printf("✅ Successfully parsed %d batch results\n", len(batchResults))
+	fmt.Printf("✅ Successfully parsed %d batch results\n", len(sentBatches))
+
+	if rejected > 0 {
+		fmt.Printf("⚠️ %d txs were rejected by the node\n", rejected)
+	}
 
-	return txHashes, nil
+	return sentTxs, nil
 }
 
 // generateBatches generates data batches based on passed in params
@@ -219,3 +308,270 @@ func generateBatches(items [][]byte, batchSize int) [][][]byte {
 
 	return batches
 }
+
+// generateLaneBatches generates data batches for each lane.
+// Empty lanes have no batches
+func generateLaneBatches(lanes [][][]byte, batchSize int) [][][][]byte {
+	laneBatches := make([][][][]byte, len(lanes))
+
+	for lane, items := range lanes {
+		if len(items) == 0 {
+			continue
+		}
+
+		laneBatches[lane] = generateBatches(items, batchSize)
+	}
+
+	return laneBatches
+}
+
+// countBatches returns the total number of batches across lanes
+func countBatches[T any](laneBatches [][]T) int {
+	numBatches := 0
+
+	for _, batches := range laneBatches {
+		numBatches += len(batches)
+	}
+
+	return numBatches
+}
+
+// StreamTransactions generates, signs and sends out transactions following the configured
+// load profile (or send schedule), until it finishes or the transaction limit is reached.
+// Sent transactions are published to the given channel before their batch is sent out,
+// and published again if they were rejected by the node.
+// The channel is closed once the stream is finished
+func (b *Batcher) StreamTransactions(
+	source TxSource,
+	cfg StreamConfig,
+	sentTxs chan<- common.SentTx,
+) error {
+	defer close(sentTxs)
+
+	fmt.Printf("\n📡 Streaming Transactions 📡\n\n")
+
+	if len(cfg.Schedule) > 0 {
+		fmt.Printf("Scheduled %d txs over %s\n", len(cfg.Schedule), cfg.duration())
+	} else {
+		fmt.Printf("Load profile:\n")
+
+		for _, phase := range cfg.Profile {
+			fmt.Printf(
+				"- %s: %d -> %d txs / s for %s\n",
+				phase.Name,
+				phase.From,
+				phase.To,
+				phase.Duration,
+			)
+		}
+	}
+
+	fmt.Println()
+
+	var (
+		sent    uint64
+		batches int
+
+		// An unknown stream size is displayed as a spinner
+		barMax = int64(-1)
+	)
+
+	if cfg.Limit > 0 {
+		barMax = int64(cfg.Limit)
+	}
+
+	var (
+		bar      = progressbar.Default(barMax, "txs sent")
+		ticker   = time.NewTicker(sendInterval)
+		duration = cfg.duration()
+
+		start = time.Now()
+	)
+
+	defer ticker.Stop()
+
+	for cfg.Limit == 0 || sent < cfg.Limit {
+		select {
+		case <-b.ctx.Done():
+			return b.ctx.Err()
+		case <-ticker.C:
+		}
+
+		elapsed := time.Since(start)
+
+		// Check if the load profile (or schedule) has finished
+		finished := elapsed >= duration
+		if finished {
+			elapsed = duration
+		}
+
+		// Figure out how many transactions should have been sent by now.
+		// If sending fell behind the schedule, the backlog is sent out right away
+		due := cfg.due(elapsed)
+		if cfg.Limit > 0 && (finished || due > cfg.Limit) {
+			due = cfg.Limit
+		}
+
+		// Scheduled streams can run without a profile,
+		// in which case all transactions belong to the first phase
+		phase := max(cfg.Profile.PhaseAt(elapsed), 0)
+
+		for sent < due {
+			// Each lane sends out (at most) a single batch
+			count := min(due-sent, uint64(cfg.BatchSize*len(b.lanes)))
+
+			sentBatches, err := b.sendStreamBatches(source, int(count), cfg.BatchSize, phase, sentTxs)
+			if err != nil {
+				return fmt.Errorf("unable to send batches, %w", err)
+			}
+
+			sent += count
+			batches += sentBatches
+
+			_ = bar.Add(int(count)) //nolint:errcheck // No need to check
+		}
+
+		if finished {
+			break
+		}
+	}
+
+	fmt.Printf("✅ Successfully streamed %d txs in %d batches\n", sent, batches)
+
+	return nil
+}
+
+// sendStreamBatches generates the given number of stream transactions,
+// and sends them out over the lanes, concurrently.
+// Returns the number of batches sent out
+func (b *Batcher) sendStreamBatches(
+	source TxSource,
+	count int,
+	batchSize int,
+	phase int,
+	sentTxs chan<- common.SentTx,
+) (int, error) {
+	var (
+		txs         = make([]*std.Tx, 0, count)
+		labels      = make([]common.TxLabel, 0, count)
+		preparedTxs = make([][]byte, 0, count)
+	)
+
+	for i := 0; i < count; i++ {
+		tx, label, err := source()
+		if err != nil {
+			return 0, fmt.Errorf("unable to generate transaction, %w", err)
+		}
+
+		txBin, err := amino.Marshal(tx)
+		if err != nil {
+			return 0, fmt.Errorf("unable to marshal tx, %w", err)
+		}
+
+		txs = append(txs, tx)
+		labels = append(labels, label)
+		preparedTxs = append(preparedTxs, txBin)
+	}
+
+	var (
+		hashLabels  = txLabels(txs, labels, preparedTxs)
+		laneBatches = generateLaneBatches(
+			b.partitionTransactions(txs, preparedTxs),
+			batchSize,
+		)
+	)
+
+	err := runLanes(len(laneBatches), func(lane int) error {
+		for _, batch := range laneBatches[lane] {
+			if err := b.sendStreamBatch(b.lanes[lane], batch, hashLabels, phase, sentTxs); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return countBatches(laneBatches), nil
+}
+
+// sendStreamBatch sends out a single batch of stream transactions
+func (b *Batcher) sendStreamBatch(
+	lane Lane,
+	txs [][]byte,
+	labels map[string]txLabel,
+	phase int,
+	sentTxs chan<- common.SentTx,
+) error {
+	var (
+		cliBatch = lane.Client.CreateBatch()
+		batchTxs = make([]common.SentTx, 0, len(txs))
+	)
+
+	for _, txBin := range txs {
+		if err := cliBatch.AddTxBroadcast(txBin); err != nil {
+			return fmt.Errorf("unable to prepare transaction, %w", err)
+		}
+
+		hash := bfttypes.Tx(txBin).Hash()
+
+		sentTx := common.SentTx{
+			Hash:     hash,
+			Phase:    phase,
+			Endpoint: lane.Endpoint,
+		}
+
+		labels[string(hash)].apply(&sentTx)
+
+		batchTxs = append(batchTxs, sentTx)
+	}
+
+	// Publish the transactions before the batch is sent out,
+	// so they can't be committed before they are being looked for
+	sentAt := time.Now()
+
+	for _, sentTx := range batchTxs {
+		sentTx.SentAt = sentAt
+
+		select {
+		case <-b.ctx.Done():
+			return b.ctx.Err()
+		case sentTxs <- sentTx:
+		}
+	}
+
+	batchResult, err := cliBatch.Execute()
+	if err != nil {
+		return fmt.Errorf("unable to batch request, %w", err)
+	}
+
+	if len(batchResult) != len(batchTxs) {
+		return errors.New("invalid number of results returned")
+	}
+
+	// Publish the transactions rejected by the node
+	for index, txResultRaw := range batchResult {
+		txResult, ok := txResultRaw.(*core_types.ResultBroadcastTx)
+		if !ok {
+			return errors.New("invalid result type returned")
+		}
+
+		if txResult.Error == nil {
+			continue
+		}
+
+		rejectedTx := batchTxs[index]
+		rejectedTx.SentAt = sentAt
+		rejectedTx.Err = txResult.Error
+
+		select {
+		case <-b.ctx.Done():
+			return b.ctx.Err()
+		case sentTxs <- rejectedTx:
+		}
+	}
+
+	return nil
+}
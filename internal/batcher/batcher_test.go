@@ -6,11 +6,18 @@ import (
 	"crypto/rand"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/gnolang/gno/tm2/pkg/amino"
 	core_types "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
 	"github.com/gnolang/gno/tm2/pkg/std"
 	"github.com/gnolang/supernova/internal/common"
+	"github.com/gnolang/supernova/internal/profile"
+	testutils "github.com/gnolang/supernova/internal/testing"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // generateRandomData generates random 32B chunks
@@ -53,7 +60,8 @@ func TestBatcher_BatchTransactions(t *testing.T) {
 		numTxs    = 100
 		batchSize = 20
 		txs       = generateTestTransactions(numTxs)
-		txHashes  = generateRandomData(t, numTxs)
+		txHashes  = make([][]byte, numTxs)
+		labels    = make([]common.TxLabel, numTxs)
 
 		broadcastTxs = make([][]byte, 0)
 		currIndex    = 0
@@ -85,22 +93,551 @@ func TestBatcher_BatchTransactions(t *testing.T) {
 		}
 	)
 
+	// The node returns the transaction hashes,
+	// which the labels are matched by
+	for index, tx := range txs {
+		txBin, err := amino.Marshal(tx)
+		require.NoError(t, err)
+
+		txHashes[index] = types.Tx(txBin).Hash()
+		labels[index] = common.TxLabel{
+			Type:    "BANK_SEND",
+			Payload: index,
+		}
+	}
+
 	// Create the batcher
 	b := NewBatcher(context.Background(), mockClient)
 
 	// Batch the transactions
-	res, err := b.BatchTransactions(txs, batchSize)
+	res, err := b.BatchTransactions(txs, labels, batchSize)
 	if err != nil {
 		t.Fatalf("unable to batch transactions, %v", err)
 	}
 
 	assert.NotNil(t, res)
 
-	if len(res.TxHashes) != numTxs {
-		t.Fatalf("invalid tx hashes returned, %d", len(res.TxHashes))
+	if len(res.SentTxs) != numTxs {
+		t.Fatalf("invalid sent txs returned, %d", len(res.SentTxs))
+	}
+
+	for index, sentTx := range res.SentTxs {
+		assert.True(t, bytes.Equal(txHashes[index], sentTx.Hash))
+		assert.False(t, sentTx.SentAt.IsZero())
+
+		// Make sure the labels are carried over
+		assert.Equal(t, labels[index].Type, sentTx.Type)
+		assert.Equal(t, labels[index].Payload, sentTx.Payload)
+	}
+}
+
+func TestBatcher_InterruptedBatching(t *testing.T) {
+	t.Parallel()
+
+	var (
+		numTxs    = 100
+		batchSize = 20
+		sentLimit = 2
+		txs       = generateTestTransactions(numTxs)
+		txHashes  = generateRandomData(t, numTxs)
+
+		ctx, cancelFn = context.WithCancel(context.Background())
+
+		currIndex = 0
+		executed  = 0
+
+		mockBatch = &mockBatch{
+			addTxBroadcastFn: func(_ []byte) error {
+				return nil
+			},
+			executeFn: func() ([]interface{}, error) {
+				res := make([]any, batchSize)
+
+				for i := 0; i < batchSize; i++ {
+					res[i] = &core_types.ResultBroadcastTx{
+						Hash: txHashes[currIndex],
+					}
+
+					currIndex++
+				}
+
+				// Interrupt the batching once the limit is reached
+				executed++
+				if executed == sentLimit {
+					cancelFn()
+				}
+
+				return res, nil
+			},
+		}
+		mockClient = &mockClient{
+			createBatchFn: func() common.Batch {
+				return mockBatch
+			},
+		}
+	)
+
+	defer cancelFn()
+
+	// Create the batcher
+	b := NewBatcher(ctx, mockClient)
+
+	// Batch the transactions
+	res, err := b.BatchTransactions(txs, nil, batchSize)
+	require.NoError(t, err)
+	require.NotNil(t, res)
+
+	// Make sure only the batches sent out before the interrupt are returned
+	require.Len(t, res.SentTxs, sentLimit*batchSize)
+
+	for index, sentTx := range res.SentTxs {
+		assert.True(t, bytes.Equal(txHashes[index], sentTx.Hash))
+	}
+}
+
+func TestBatcher_StreamTransactions(t *testing.T) {
+	t.Parallel()
+
+	var (
+		numTxs    = 50
+		batchSize = 10
+		txs       = generateTestTransactions(numTxs)
+
+		pending   = 0
+		currIndex = 0
+
+		mockBatch = &mockBatch{
+			addTxBroadcastFn: func(_ []byte) error {
+				pending++
+
+				return nil
+			},
+			executeFn: func() ([]interface{}, error) {
+				res := make([]any, pending)
+
+				for i := 0; i < pending; i++ {
+					res[i] = &core_types.ResultBroadcastTx{}
+				}
+
+				pending = 0
+
+				return res, nil
+			},
+		}
+		mockClient = &mockClient{
+			createBatchFn: func() common.Batch {
+				return mockBatch
+			},
+		}
+	)
+
+	source := func() (*std.Tx, common.TxLabel, error) {
+		tx := txs[currIndex]
+		label := common.TxLabel{
+			Type:    "BANK_SEND",
+			Payload: currIndex,
+		}
+		currIndex++
+
+		return tx, label, nil
+	}
+
+	// Create the batcher
+	b := NewBatcher(context.Background(), mockClient)
+
+	// Stream the transactions
+	sentTxs := make(chan common.SentTx, numTxs)
+
+	err := b.StreamTransactions(
+		source,
+		StreamConfig{
+			Profile:   profile.NewConstant(1000, time.Second),
+			Limit:     uint64(numTxs),
+			BatchSize: batchSize,
+		},
+		sentTxs,
+	)
+	require.NoError(t, err)
+
+	// Make sure all transactions were published, in order
+	index := 0
+
+	for sentTx := range sentTxs {
+		txBin, err := amino.Marshal(txs[index])
+		require.NoError(t, err)
+
+		assert.Equal(t, types.Tx(txBin).Hash(), sentTx.Hash)
+		assert.Equal(t, 0, sentTx.Phase)
+		assert.Equal(t, "BANK_SEND", sentTx.Type)
+		assert.Equal(t, index, sentTx.Payload)
+
+		index++
+	}
+
+	assert.Equal(t, numTxs, index)
+}
+
+func TestBatcher_ScheduledTransactions(t *testing.T) {
+	t.Parallel()
+
+	var (
+		numTxs    = 20
+		batchSize = 5
+		txs       = generateTestTransactions(numTxs)
+
+		// Half of the transactions are due right away,
+		// and the other half only after the delay
+		delay    = 300 * time.Millisecond
+		schedule = make([]time.Duration, numTxs)
+
+		pending   = 0
+		currIndex = 0
+
+		mockBatch = &mockBatch{
+			addTxBroadcastFn: func(_ []byte) error {
+				pending++
+
+				return nil
+			},
+			executeFn: func() ([]interface{}, error) {
+				res := make([]any, pending)
+
+				for i := 0; i < pending; i++ {
+					res[i] = &core_types.ResultBroadcastTx{}
+				}
+
+				pending = 0
+
+				return res, nil
+			},
+		}
+		mockClient = &mockClient{
+			createBatchFn: func() common.Batch {
+				return mockBatch
+			},
+		}
+	)
+
+	for i := numTxs / 2; i < numTxs; i++ {
+		schedule[i] = delay
+	}
+
+	source := func() (*std.Tx, common.TxLabel, error) {
+		tx := txs[currIndex]
+		currIndex++
+
+		return tx, common.TxLabel{}, nil
+	}
+
+	// Create the batcher
+	b := NewBatcher(context.Background(), mockClient)
+
+	// Stream the transactions, on schedule
+	sentTxs := make(chan common.SentTx, numTxs)
+	start := time.Now()
+
+	err := b.StreamTransactions(
+		source,
+		StreamConfig{
+			Limit:     uint64(numTxs),
+			BatchSize: batchSize,
+			Schedule:  schedule,
+		},
+		sentTxs,
+	)
+	require.NoError(t, err)
+
+	// Make sure the stream waited for the delayed transactions
+	assert.GreaterOrEqual(t, time.Since(start), delay)
+
+	// Make sure all transactions were published, in order
+	index := 0
+
+	for sentTx := range sentTxs {
+		txBin, err := amino.Marshal(txs[index])
+		require.NoError(t, err)
+
+		assert.Equal(t, types.Tx(txBin).Hash(), sentTx.Hash)
+		assert.Equal(t, 0, sentTx.Phase)
+
+		index++
+	}
+
+	assert.Equal(t, numTxs, index)
+}
+
+func TestBatcher_RejectedTransactions(t *testing.T) {
+	t.Parallel()
+
+	var (
+		numTxs   = 10
+		txs      = generateTestTransactions(numTxs)
+		txHashes = generateRandomData(t, numTxs)
+
+		mockBatch = &mockBatch{
+			executeFn: func() ([]interface{}, error) {
+				res := make([]any, numTxs)
+
+				for i := 0; i < numTxs; i++ {
+					txResult := &core_types.ResultBroadcastTx{
+						Hash: txHashes[i],
+					}
+
+					// Every other transaction is rejected
+					if i%2 == 0 {
+						txResult.Error = std.InvalidSequenceError{}
+					}
+
+					res[i] = txResult
+				}
+
+				return res, nil
+			},
+		}
+		mockClient = &mockClient{
+			createBatchFn: func() common.Batch {
+				return mockBatch
+			},
+		}
+	)
+
+	// Create the batcher
+	b := NewBatcher(context.Background(), mockClient)
+
+	// Batch the transactions
+	res, err := b.BatchTransactions(txs, nil, numTxs)
+	require.NoError(t, err)
+
+	require.Len(t, res.SentTxs, numTxs)
+
+	// Make sure the rejected transactions are marked
+	for index, sentTx := range res.SentTxs {
+		if index%2 == 0 {
+			assert.ErrorIs(t, sentTx.Err, std.InvalidSequenceError{})
+
+			continue
+		}
+
+		assert.NoError(t, sentTx.Err)
 	}
+}
+
+func TestBatcher_ConcurrentLanes(t *testing.T) {
+	t.Parallel()
 
-	for index, txHash := range txHashes {
-		assert.True(t, bytes.Equal(txHash, txHashes[index]))
+	var (
+		numLanes    = 2
+		numAccounts = 4
+		numTxs      = 40
+		batchSize   = 3
+
+		keys = testutils.GenerateAccounts(t, numAccounts)
+		txs  = make([]*std.Tx, numTxs)
+
+		laneTxs = make([][][]byte, numLanes)
+		lanes   = make([]Lane, numLanes)
+	)
+
+	// Generate the transactions, rotating the signer accounts
+	for i := 0; i < numTxs; i++ {
+		txs[i] = &std.Tx{
+			Msgs: []std.Msg{
+				bank.MsgSend{
+					FromAddress: keys[i%numAccounts].PubKey().Address(),
+				},
+			},
+			Memo: fmt.Sprintf("tx-%d", i),
+		}
 	}
+
+	// Create the lane clients, which keep track of sent txs
+	for lane := 0; lane < numLanes; lane++ {
+		pending := 0
+
+		mockBatch := &mockBatch{
+			addTxBroadcastFn: func(tx []byte) error {
+				laneTxs[lane] = append(laneTxs[lane], tx)
+				pending++
+
+				return nil
+			},
+			executeFn: func() ([]interface{}, error) {
+				res := make([]any, pending)
+
+				for i := 0; i < pending; i++ {
+					res[i] = &core_types.ResultBroadcastTx{}
+				}
+
+				pending = 0
+
+				return res, nil
+			},
+		}
+
+		lanes[lane] = Lane{
+			Client: &mockClient{
+				createBatchFn: func() common.Batch {
+					return mockBatch
+				},
+			},
+			Endpoint: fmt.Sprintf("http://node-%d:26657", lane),
+		}
+	}
+
+	// Create the batcher
+	b := NewBatcher(context.Background(), &mockClient{}, lanes...)
+
+	// Batch the transactions
+	res, err := b.BatchTransactions(txs, nil, batchSize)
+	require.NoError(t, err)
+
+	require.Len(t, res.SentTxs, numTxs)
+
+	// Make sure the lane endpoints were noted down
+	for index, sentTx := range res.SentTxs {
+		assert.Equal(t, lanes[index/(numTxs/numLanes)].Endpoint, sentTx.Endpoint)
+	}
+
+	// Make sure each account was sent out
+	// over a single lane, in the original order
+	for lane := 0; lane < numLanes; lane++ {
+		require.Len(t, laneTxs[lane], numTxs/numLanes)
+
+		for index, txBin := range laneTxs[lane] {
+			var tx std.Tx
+
+			require.NoError(t, amino.Unmarshal(txBin, &tx))
+
+			// Accounts are assigned to lanes in a round-robin fashion,
+			// so each lane contains every other transaction
+			expectedIndex := index*numLanes + lane
+
+			assert.Equal(t, fmt.Sprintf("tx-%d", expectedIndex), tx.Memo)
+		}
+	}
+}
+
+func TestBatcher_StreamLanes(t *testing.T) {
+	t.Parallel()
+
+	var (
+		numLanes    = 3
+		numAccounts = 5
+		numRounds   = 4
+		batchSize   = 2
+
+		keys = testutils.GenerateAccounts(t, numAccounts)
+
+		laneSigners = make([][]string, numLanes)
+		lanes       = make([]Lane, numLanes)
+	)
+
+	// Create the lane clients, which keep track of the signers they send for
+	for lane := 0; lane < numLanes; lane++ {
+		pending := 0
+
+		mockBatch := &mockBatch{
+			addTxBroadcastFn: func(txBin []byte) error {
+				var tx std.Tx
+
+				if err := amino.Unmarshal(txBin, &tx); err != nil {
+					return err
+				}
+
+				laneSigners[lane] = append(laneSigners[lane], txSigner(&tx))
+				pending++
+
+				return nil
+			},
+			executeFn: func() ([]interface{}, error) {
+				res := make([]any, pending)
+
+				for i := 0; i < pending; i++ {
+					res[i] = &core_types.ResultBroadcastTx{}
+				}
+
+				pending = 0
+
+				return res, nil
+			},
+		}
+
+		lanes[lane] = Lane{
+			Client: &mockClient{
+				createBatchFn: func() common.Batch {
+					return mockBatch
+				},
+			},
+			Endpoint: fmt.Sprintf("http://node-%d:26657", lane),
+		}
+	}
+
+	// Create the batcher
+	b := NewBatcher(context.Background(), &mockClient{}, lanes...)
+
+	var (
+		sentTxs         = make(chan common.SentTx, numAccounts)
+		hashSigners     = make(map[string]string)
+		signerEndpoints = make(map[string]string)
+	)
+
+	// Send out several stream ticks, in which
+	// the signers show up in a different order
+	for round := 0; round < numRounds; round++ {
+		index := 0
+
+		source := func() (*std.Tx, common.TxLabel, error) {
+			signer := keys[(round+index)%numAccounts].PubKey().Address()
+
+			tx := &std.Tx{
+				Msgs: []std.Msg{
+					bank.MsgSend{
+						FromAddress: signer,
+					},
+				},
+				Memo: fmt.Sprintf("tx-%d-%d", round, index),
+			}
+
+			txBin, err := amino.Marshal(tx)
+			if err != nil {
+				return nil, common.TxLabel{}, err
+			}
+
+			hashSigners[string(types.Tx(txBin).Hash())] = signer.String()
+			index++
+
+			return tx, common.TxLabel{}, nil
+		}
+
+		_, err := b.sendStreamBatches(source, numAccounts, batchSize, 0, sentTxs)
+		require.NoError(t, err)
+
+		// Make sure each signer was always sent to the same endpoint
+		for i := 0; i < numAccounts; i++ {
+			sentTx := <-sentTxs
+			signer := hashSigners[string(sentTx.Hash)]
+
+			if endpoint, seen := signerEndpoints[signer]; seen {
+				assert.Equal(t, endpoint, sentTx.Endpoint, "signer %s switched endpoints", signer)
+			}
+
+			signerEndpoints[signer] = sentTx.Endpoint
+		}
+	}
+
+	// Make sure each signer always used the same lane client
+	signerLanes := make(map[string]int)
+
+	for lane, signers := range laneSigners {
+		for _, signer := range signers {
+			previous, seen := signerLanes[signer]
+			if seen {
+				assert.Equal(t, previous, lane, "signer %s switched lanes", signer)
+			}
+
+			signerLanes[signer] = lane
+		}
+	}
+
+	assert.Len(t, signerLanes, numAccounts)
 }
@@ -0,0 +1,103 @@
+package batcher
+
+import (
+	"errors"
+	"sync"
+
+	bfttypes "github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/supernova/internal/common"
+)
+
+// partitionTransactions partitions the prepared transactions into send lanes,
+// based on their signer account. All transactions of a single account
+// are placed in the same lane, in their original order, so the account
+// sequence order is preserved when the lanes are sent out concurrently.
+// The account lanes are kept across calls, so streamed transactions
+// of an account are sent to the same endpoint for the whole run
+func (b *Batcher) partitionTransactions(txs []*std.Tx, preparedTxs [][]byte) [][][]byte {
+	lanes := make([][][]byte, len(b.lanes))
+
+	for index, tx := range txs {
+		signer := txSigner(tx)
+
+		// Accounts are assigned to lanes in a round-robin
+		// fashion, the first time they are seen
+		lane, ok := b.accountLanes[signer]
+		if !ok {
+			lane = len(b.accountLanes) % len(b.lanes)
+			b.accountLanes[signer] = lane
+		}
+
+		lanes[lane] = append(lanes[lane], preparedTxs[index])
+	}
+
+	return lanes
+}
+
+// txLabel is the label of a single prepared transaction
+type txLabel struct {
+	common.TxLabel
+
+	msgs int // the number of messages in the tx
+}
+
+// apply labels the sent transaction
+func (l txLabel) apply(sentTx *common.SentTx) {
+	sentTx.Type = l.Type
+	sentTx.Payload = l.Payload
+	sentTx.Msgs = l.msgs
+}
+
+// txLabels returns the labels of the prepared transactions, by their
+// transaction hash. The runtime labels are matched to the transactions by index, if any
+func txLabels(txs []*std.Tx, labels []common.TxLabel, preparedTxs [][]byte) map[string]txLabel {
+	hashLabels := make(map[string]txLabel, len(txs))
+
+	for index, tx := range txs {
+		label := txLabel{
+			msgs: len(tx.Msgs),
+		}
+
+		if index < len(labels) {
+			label.TxLabel = labels[index]
+		}
+
+		hashLabels[string(bfttypes.Tx(preparedTxs[index]).Hash())] = label
+	}
+
+	return hashLabels
+}
+
+// txSigner returns the address of the first signer of the transaction, if any
+func txSigner(tx *std.Tx) string {
+	signers := tx.GetSigners()
+	if len(signers) == 0 {
+		return ""
+	}
+
+	return signers[0].String()
+}
+
+// runLanes runs the given callback for each lane concurrently,
+// and waits for all of them to finish
+func runLanes(numLanes int, laneFn func(lane int) error) error {
+	var (
+		errs = make([]error, numLanes)
+		wg   sync.WaitGroup
+	)
+
+	for lane := 0; lane < numLanes; lane++ {
+		wg.Add(1)
+
+		go func(lane int) {
+			defer wg.Done()
+
+			errs[lane] = laneFn(lane)
+		}(lane)
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
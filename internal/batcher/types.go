@@ -2,8 +2,12 @@ package batcher
 
 import (
 	"context"
+	"sort"
+	"time"
 
+	"github.com/gnolang/gno/tm2/pkg/std"
 	"github.com/gnolang/supernova/internal/common"
+	"github.com/gnolang/supernova/internal/profile"
 )
 
 type Client interface {
@@ -11,8 +15,53 @@ type Client interface {
 	GetLatestBlockHeight(ctx context.Context) (int64, error)
 }
 
+// Lane is a single send lane,
+// with its own connection to an endpoint
+type Lane struct {
+	Client   Client // the lane connection
+	Endpoint string // the endpoint of the lane connection
+}
+
+// TxSource generates the next signed
+// transaction to be streamed, along with its label
+type TxSource func() (*std.Tx, common.TxLabel, error)
+
 // TxBatchResult contains batching results
 type TxBatchResult struct {
-	TxHashes   [][]byte // the tx hashes
-	StartBlock int64    // the initial block for querying
+	SentTxs    []common.SentTx // the sent txs
+	StartBlock int64           // the initial block for querying
+}
+
+// StreamConfig is the configuration
+// for the rate-controlled transaction stream
+type StreamConfig struct {
+	Profile   profile.Profile // the load profile of the stream
+	Limit     uint64          // the maximum number of txs to send, if any
+	BatchSize int             // the maximum size of a single batch
+
+	// Schedule is the send offset of each transaction, relative to the stream start, if any.
+	// Scheduled transactions are sent out once their offset elapses, instead of following
+	// the profile send rate. The offsets are expected to be in ascending order
+	Schedule []time.Duration
+}
+
+// duration returns the total duration of the stream
+func (c StreamConfig) duration() time.Duration {
+	if len(c.Schedule) > 0 {
+		return c.Schedule[len(c.Schedule)-1]
+	}
+
+	return c.Profile.Duration()
+}
+
+// due returns the number of transactions that are due to be sent out,
+// the given time after the stream start
+func (c StreamConfig) due(elapsed time.Duration) uint64 {
+	if len(c.Schedule) > 0 {
+		return uint64(sort.Search(len(c.Schedule), func(i int) bool {
+			return c.Schedule[i] > elapsed
+		}))
+	}
+
+	return uint64(c.Profile.Due(elapsed))
 }
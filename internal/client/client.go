@@ -97,6 +97,24 @@ func (h *Client) BroadcastTransaction(ctx context.Context, tx *std.Tx) error {
 	return nil
 }
 
+func (h *Client) BroadcastTransactionSync(ctx context.Context, tx *std.Tx) error {
+	marshalledTx, err := amino.Marshal(tx)
+	if err != nil {
+		return fmt.Errorf("unable to marshal transaction, %w", err)
+	}
+
+	res, err := h.conn.BroadcastTxSync(ctx, marshalledTx)
+	if err != nil {
+		return fmt.Errorf("unable to broadcast transaction, %w", err)
+	}
+
+	if res.Error != nil {
+		return fmt.Errorf("broadcast transaction check failed, %w", res.Error)
+	}
+
+	return nil
+}
+
 func (h *Client) GetAccount(ctx context.Context, address string) (*gnoland.GnoAccount, error) {
 	queryResult, err := h.conn.ABCIQuery(
 		ctx,
@@ -119,20 +137,6 @@ func (h *Client) GetAccount(ctx context.Context, address string) (*gnoland.GnoAc
 	return &acc, nil
 }
 
-func (h *Client) GetBlockGasUsed(ctx context.Context, height int64) (int64, error) {
-	blockRes, err := h.conn.BlockResults(ctx, &height)
-	if err != nil {
-		return 0, fmt.Errorf("unable to fetch block results, %w", err)
-	}
-
-	gasUsed := int64(0)
-	for _, tx := range blockRes.Results.DeliverTxs {
-		gasUsed += tx.GasUsed
-	}
-
-	return gasUsed, nil
-}
-
 func (h *Client) GetBlockGasLimit(ctx context.Context, height int64) (int64, error) {
 	consensusParams, err := h.conn.ConsensusParams(ctx, &height)
 	if err != nil {
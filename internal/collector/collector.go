@@ -2,68 +2,158 @@ package collector
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"time"
 
-	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/supernova/internal/common"
+	"github.com/gnolang/supernova/internal/profile"
 	"github.com/schollz/progressbar/v3"
 )
 
-var errTimeout = errors.New("collector timed out")
+// minPollInterval is the minimum interval
+// in which the latest block height is polled
+const minPollInterval = time.Millisecond
 
 // Collector is the transaction / block stat
 // collector.
 // This implementation will heavily change when
-// transaction indexing is introduced
+// transaction indexing is introduced.
+//
+// New blocks are discovered by polling the latest block height,
+// for both HTTP and WS clients. The TM2 JSON-RPC does not expose
+// event subscriptions (ex. NewBlock), so there is no event-driven
+// alternative to polling, even over WS
 type Collector struct {
 	cli Client
 	ctx context.Context
 
-	requestTimeout time.Duration
+	pollInterval   time.Duration
+	collectTimeout time.Duration
+	idleBlocks     int64
 }
 
 // NewCollector creates a new instance of the collector
-func NewCollector(ctx context.Context, cli Client) *Collector {
+func NewCollector(ctx context.Context, cli Client, cfg Config) *Collector {
 	return &Collector{
 		cli:            cli,
-		requestTimeout: time.Second * 2,
+		pollInterval:   cfg.PollInterval,
+		collectTimeout: cfg.CollectTimeout,
+		idleBlocks:     int64(cfg.IdleBlocks),
 		ctx:            ctx,
 	}
 }
 
-// GetRunResult generates the run result for the passed in transaction hashes and start range
+// GetRunResult generates the run result for the passed in transactions and start range
 func (c *Collector) GetRunResult(
-	txHashes [][]byte,
+	sentTxs []common.SentTx,
 	startBlock int64,
 	startTime time.Time,
 ) (*RunResult, error) {
+	fmt.Printf("\n📊 Collecting Results 📊\n\n")
+
 	var (
-		blockResults = make([]*BlockResult, 0)
-		timeout      = time.After(5 * time.Minute)
-		start        = startBlock
-		txMap        = newTxLookup(txHashes)
-		processed    = 0
+		txMap  = newTxLookup()
+		result = newRunCollection(startTime, nil)
 	)
 
-	fmt.Printf("\n📊 Collecting Results 📊\n\n")
+	for _, sentTx := range sentTxs {
+		txMap.add(sentTx)
+	}
 
-	bar := progressbar.Default(int64(len(txHashes)), "txs collected")
+	bar := progressbar.Default(int64(txMap.size()), "txs collected")
+
+	return c.collect(txMap, nil, startBlock, result, bar)
+}
+
+// StreamRunResult generates the run result for transactions that are streamed
+// while the run is in progress, following the given load profile.
+// Collection is finished once the transaction channel is closed,
+// and all the streamed transactions have been committed
+func (c *Collector) StreamRunResult(
+	sentTxs <-chan common.SentTx,
+	startBlock int64,
+	startTime time.Time,
+	loadProfile profile.Profile,
+) (*RunResult, error) {
+	var (
+		// The collector is running alongside the stream,
+		// so it doesn't display its progress
+		bar    = progressbar.DefaultSilent(-1, "txs collected")
+		result = newRunCollection(startTime, loadProfile)
+	)
+
+	return c.collect(newTxLookup(), sentTxs, startBlock, result, bar)
+}
+
+// collect collects the block results for all transactions in the lookup map,
+// as well as for any transaction received over the (optional) transaction channel.
+// Transactions that are not committed by the time the collection times out,
+// or goes idle, are reported as never included, and the run result is marked as partial.
+// If the collection is interrupted (context canceled), the results gathered so far
+// are returned as a partial run result as well
+func (c *Collector) collect(
+	txMap *txLookup,
+	sentTxs <-chan common.SentTx,
+	startBlock int64,
+	result *runCollection,
+	bar *progressbar.ProgressBar,
+) (*RunResult, error) {
+	var (
+		start     = startBlock
+		processed = 0
+
+		// The number of consecutive blocks without run txs,
+		// counted once all transactions are known
+		idle = int64(0)
+
+		// The timeout is started once all transactions are known
+		timeout <-chan time.Time
+	)
+
+	if sentTxs == nil {
+		timeout = time.After(c.collectTimeout)
+	}
+
+	// The ticker is kept across the loop iterations, so the streamed
+	// transactions don't postpone the polling of new blocks
+	ticker := time.NewTicker(max(c.pollInterval, minPollInterval))
+	defer ticker.Stop()
 
 	for {
 		// Check if all original transactions
 		// were processed
 		//nolint:staticcheck
-		if processed >= len(txHashes) {
+		if sentTxs == nil && processed >= txMap.size() {
 			break
 		}
 
 		select {
+		case <-c.ctx.Done():
+			return interrupted(txMap, sentTxs, result, processed), nil
 		case <-timeout:
-			return nil, errTimeout
-		case <-time.After(c.requestTimeout):
+			fmt.Printf(
+				"⚠️ Collector timed out, %d txs were never included\n",
+				txMap.size()-processed,
+			)
+
+			return partialResult(txMap, result), nil
+		case sentTx, more := <-sentTxs:
+			if !more {
+				// All transactions have been received
+				sentTxs = nil
+				timeout = time.After(c.collectTimeout)
+
+				continue
+			}
+
+			txMap.add(sentTx)
+		case <-ticker.C:
 			latest, err := c.cli.GetLatestBlockHeight(c.ctx)
 			if err != nil {
+				if c.ctx.Err() != nil {
+					return interrupted(txMap, sentTxs, result, processed), nil
+				}
+
 				return nil, fmt.Errorf("unable to fetch latest block height, %w", err)
 			}
 
@@ -72,91 +162,167 @@ func (c *Collector) GetRunResult(
 				continue
 			}
 
+			// Any transaction committed up until the latest block
+			// has been published by now
+			streaming := sentTxs != nil
+			sentTxs = drainSentTxs(txMap, sentTxs)
+
+			observedAt := time.Now()
+
 			// Iterate over each block and find relevant transactions
 			for blockNum := start; blockNum <= latest; blockNum++ {
-				// Fetch the block
-				block, err := c.cli.GetBlock(c.ctx, &blockNum)
+				belong, err := c.collectBlock(blockNum, txMap, result, observedAt)
 				if err != nil {
-					return nil, fmt.Errorf("unable to fetch block, %w", err)
-				}
+					if c.ctx.Err() != nil {
+						return interrupted(txMap, sentTxs, result, processed), nil
+					}
 
-				// Check if any of the block transactions are the ones
-				// sent out in the stress test
-				belong := txMap.anyBelong(block.Block.Txs)
-				if belong == 0 {
-					continue
+					return nil, err
 				}
 
 				processed += belong
 				_ = bar.Add(belong) //nolint:errcheck // No need to check
 
-				// Fetch the total gas used by transactions
-				blockGasUsed, err := c.cli.GetBlockGasUsed(c.ctx, blockNum)
-				if err != nil {
-					return nil, fmt.Errorf("unable to fetch block gas used, %w", err)
-				}
-
-				// Fetch the block gas limit
-				blockGasLimit, err := c.cli.GetBlockGasLimit(c.ctx, blockNum)
-				if err != nil {
-					return nil, fmt.Errorf("unable to fetch block gas limit, %w", err)
+				// Blocks are only idle once there are no more
+				// transactions that can be sent out
+				idle++
+				if belong > 0 || sentTxs != nil {
+					idle = 0
 				}
+			}
 
-				blockResults = append(blockResults, &BlockResult{
-					Number:       blockNum,
-					Time:         block.BlockMeta.Header.Time,
-					Transactions: block.BlockMeta.Header.NumTxs,
-					GasUsed:      blockGasUsed,
-					GasLimit:     blockGasLimit,
-				})
+			if streaming && sentTxs == nil {
+				// All transactions have been received
+				timeout = time.After(c.collectTimeout)
 			}
 
 			// Update the iteration range
 			start = latest + 1
+
+			// Check if the collection went idle
+			if c.idleBlocks > 0 && idle >= c.idleBlocks && processed < txMap.size() {
+				fmt.Printf(
+					"⚠️ Collector idle for %d blocks, %d txs were never included\n",
+					idle,
+					txMap.size()-processed,
+				)
+
+				return partialResult(txMap, result), nil
+			}
 		}
 	}
 
-	return &RunResult{
-		AverageTPS: calculateTPS(
-			startTime,
-			len(txHashes),
-		),
-		Blocks: blockResults,
-	}, nil
+	return result.getRunResult(txMap), nil
 }
 
-// txLookup is a simple lookup map for transaction hashes
-type txLookup struct {
-	lookup map[string]struct{}
-}
+// collectBlock collects the results of the run transactions committed in the given block,
+// if any, and returns the number of run transactions found in the block
+func (c *Collector) collectBlock(
+	blockNum int64,
+	txMap *txLookup,
+	result *runCollection,
+	observedAt time.Time,
+) (int, error) {
+	// Fetch the block
+	block, err := c.cli.GetBlock(c.ctx, &blockNum)
+	if err != nil {
+		return 0, fmt.Errorf("unable to fetch block, %w", err)
+	}
+
+	// Check if any of the block transactions are the ones
+	// sent out in the stress test
+	belong := txMap.belonging(block.Block.Txs)
+	if len(belong) == 0 {
+		return 0, nil
+	}
+
+	// Fetch the block results, which contain
+	// the execution result of each transaction
+	blockResults, err := c.cli.GetBlockResults(c.ctx, &blockNum)
+	if err != nil {
+		return 0, fmt.Errorf("unable to fetch block results, %w", err)
+	}
+
+	deliverTxs := blockResults.Results.DeliverTxs
 
-// newTxLookup creates a new instance of the tx lookup map
-func newTxLookup(txs [][]byte) *txLookup {
-	lookup := make(map[string]struct{})
+	// Calculate the total gas used by transactions
+	blockGasUsed := int64(0)
+	for _, deliverTx := range deliverTxs {
+		blockGasUsed += deliverTx.GasUsed
+	}
 
-	for _, tx := range txs {
-		lookup[string(tx)] = struct{}{}
+	// Fetch the block gas limit
+	blockGasLimit, err := c.cli.GetBlockGasLimit(c.ctx, blockNum)
+	if err != nil {
+		return 0, fmt.Errorf("unable to fetch block gas limit, %w", err)
 	}
 
-	return &txLookup{
-		lookup: lookup,
+	// Match the run transactions with their execution results
+	for _, tx := range belong {
+		if tx.index < len(deliverTxs) {
+			tx.deliverTx = &deliverTxs[tx.index]
+		}
 	}
+
+	result.addBlock(
+		&BlockResult{
+			Number:       blockNum,
+			Time:         block.BlockMeta.Header.Time,
+			Transactions: block.BlockMeta.Header.NumTxs,
+			GasUsed:      blockGasUsed,
+			GasLimit:     blockGasLimit,
+		},
+		belong,
+		observedAt,
+	)
+
+	return len(belong), nil
+}
+
+// interrupted generates the partial run result for an interrupted collection.
+// Transactions that were not committed by then are reported as never included
+func interrupted(
+	txMap *txLookup,
+	sentTxs <-chan common.SentTx,
+	result *runCollection,
+	processed int,
+) *RunResult {
+	// Account for any transaction that was sent out,
+	// but not yet picked up by the collector
+	drainSentTxs(txMap, sentTxs)
+
+	fmt.Printf(
+		"⚠️ Collection interrupted, %d txs were not yet included\n",
+		txMap.size()-processed,
+	)
+
+	return partialResult(txMap, result)
 }
 
-// anyBelong returns the number of transactions
-// that have been found in the lookup map
-func (t *txLookup) anyBelong(txs types.Txs) int {
-	belong := 0
+// partialResult generates the run result for a collection
+// that ended before all transactions were committed
+func partialResult(txMap *txLookup, result *runCollection) *RunResult {
+	runResult := result.getRunResult(txMap)
+	runResult.Partial = true
+
+	return runResult
+}
 
-	for _, tx := range txs {
-		txHash := tx.Hash()
+// drainSentTxs adds all pending transactions from the channel to the lookup map,
+// without blocking. If the channel is closed, nil is returned
+func drainSentTxs(txMap *txLookup, sentTxs <-chan common.SentTx) <-chan common.SentTx {
+	for {
+		select {
+		case sentTx, more := <-sentTxs:
+			if !more {
+				return nil
+			}
 
-		if _, ok := t.lookup[string(txHash)]; ok {
-			belong++
+			txMap.add(sentTx)
+		default:
+			return sentTxs
 		}
 	}
-
-	return belong
 }
 
 // calculateTPS calculates the TPS for the sequence
@@ -3,13 +3,20 @@ package collector
 import (
 	"context"
 	"crypto/rand"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	core_types "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/state"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/crypto/tmhash"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/supernova/internal/common"
+	"github.com/gnolang/supernova/internal/profile"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // generateRandomData generates random 32B chunks
@@ -32,6 +39,20 @@ func generateRandomData(t *testing.T, count int) [][]byte {
 	return data
 }
 
+// newBlockResults creates block results for
+// a single transaction, using the given gas
+func newBlockResults(gasUsed int64) *core_types.ResultBlockResults {
+	return &core_types.ResultBlockResults{
+		Results: &state.ABCIResponses{
+			DeliverTxs: []abci.ResponseDeliverTx{
+				{
+					GasUsed: gasUsed,
+				},
+			},
+		},
+	}
+}
+
 func TestCollector_GetRunResults(t *testing.T) {
 	t.Parallel()
 
@@ -48,10 +69,13 @@ func TestCollector_GetRunResults(t *testing.T) {
 	}
 
 	txs := generateRandomData(t, numTxs)
-	txHashes := make([][]byte, numTxs)
+	sentTxs := make([]common.SentTx, numTxs)
 
 	for i := 0; i < numTxs; i++ {
-		txHashes[i] = tmhash.Sum(txs[i])
+		sentTxs[i] = common.SentTx{
+			Hash:   tmhash.Sum(txs[i]),
+			SentAt: startTime,
+		}
 	}
 
 	var (
@@ -91,22 +115,23 @@ func TestCollector_GetRunResults(t *testing.T) {
 
 				return gasLimit, nil
 			},
-			getBlockGasUsedFn: func(ctx context.Context, height int64) (int64, error) {
-				if height > int64(numTxs) {
+			getBlockResultsFn: func(ctx context.Context, height *int64) (*core_types.ResultBlockResults, error) {
+				if *height > int64(numTxs) {
 					t.Fatalf("invalid height requested")
 				}
 
-				return gasUsed, nil
+				return newBlockResults(gasUsed), nil
 			},
 		}
 	)
 
 	// Create the collector
-	c := NewCollector(context.Background(), mockClient)
-	c.requestTimeout = time.Second * 0
+	c := NewCollector(context.Background(), mockClient, Config{
+		CollectTimeout: time.Minute * 5,
+	})
 
 	// Collect the results
-	result, err := c.GetRunResult(txHashes, 1, startTime)
+	result, err := c.GetRunResult(sentTxs, 1, startTime)
 	if err != nil {
 		t.Fatalf("unable to get run results, %v", err)
 	}
@@ -124,4 +149,599 @@ func TestCollector_GetRunResults(t *testing.T) {
 		assert.Equal(t, gasLimit, block.GasLimit)
 		assert.Equal(t, int64(1), block.Transactions)
 	}
+
+	// Make sure the latencies are valid
+	// (the block of transaction i is committed i seconds after the start)
+	assert.Len(t, result.Transactions, numTxs)
+
+	assert.Equal(t, 49*time.Second, result.Latency.Commit.P50)
+	assert.Equal(t, 89*time.Second, result.Latency.Commit.P90)
+	assert.Equal(t, 98*time.Second, result.Latency.Commit.P99)
+	assert.Equal(t, 99*time.Second, result.Latency.Commit.Max)
+	assert.Positive(t, result.Latency.Observed.Max)
+}
+
+func TestCollector_StreamRunResult(t *testing.T) {
+	t.Parallel()
+
+	var (
+		numTxs    = 10
+		startTime = time.Now()
+		txs       = generateRandomData(t, numTxs)
+		sentTxs   = make(chan common.SentTx, numTxs)
+
+		// Blocks are created every second, so the first 4 blocks
+		// fall into the first phase, and the rest into the second one
+		loadProfile = profile.Profile{
+			{Name: "constant-1", Shape: profile.Constant, From: 1, To: 1, Duration: 5 * time.Second},
+			{Name: "constant-2", Shape: profile.Constant, From: 1, To: 1, Duration: 6 * time.Second},
+		}
+	)
+
+	mockClient := &mockClient{
+		getBlockFn: func(_ context.Context, height *int64) (*core_types.ResultBlock, error) {
+			if *height > int64(numTxs) {
+				t.Fatalf("invalid height requested")
+			}
+
+			// Each block also holds transactions
+			// that were not sent out by the run
+			return &core_types.ResultBlock{
+				BlockMeta: &types.BlockMeta{
+					Header: types.Header{
+						Height: *height,
+						Time:   startTime.Add(time.Duration(*height) * time.Second),
+						NumTxs: 3,
+					},
+				},
+				Block: &types.Block{
+					Data: types.Data{
+						Txs: []types.Tx{
+							txs[*height-1],
+							[]byte("foreign-1"),
+							[]byte("foreign-2"),
+						},
+					},
+				},
+			}, nil
+		},
+		getLatestBlockHeightFn: func(_ context.Context) (int64, error) {
+			return int64(numTxs), nil
+		},
+		getBlockGasLimitFn: func(_ context.Context, _ int64) (int64, error) {
+			return 1000, nil
+		},
+		getBlockResultsFn: func(_ context.Context, _ *int64) (*core_types.ResultBlockResults, error) {
+			return newBlockResults(500), nil
+		},
+	}
+
+	// Stream the transactions, split evenly between the phases
+	for index, tx := range txs {
+		sentTxs <- common.SentTx{
+			Hash:  tmhash.Sum(tx),
+			Phase: index / (numTxs / 2),
+		}
+	}
+
+	close(sentTxs)
+
+	// Create the collector
+	c := NewCollector(context.Background(), mockClient, Config{
+		CollectTimeout: time.Minute * 5,
+	})
+
+	// Collect the results
+	result, err := c.StreamRunResult(sentTxs, 1, startTime, loadProfile)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	assert.NotZero(t, result.AverageTPS)
+	assert.Len(t, result.Blocks, numTxs)
+
+	// Make sure the phase results are valid
+	require.Len(t, result.Phases, len(loadProfile))
+
+	for index, phase := range result.Phases {
+		assert.Equal(t, loadProfile[index].Name, phase.Name)
+		assert.Equal(t, numTxs/2, phase.Sent)
+		assert.Equal(t, numTxs/2, phase.Committed)
+		assert.InDelta(t, 50.0, phase.AverageUtilization, 0.001)
+	}
+
+	assert.Equal(t, 4, result.Phases[0].Blocks)
+	assert.Equal(t, 6, result.Phases[1].Blocks)
+	assert.InDelta(t, 4.0/5.0, result.Phases[0].TPS, 0.001)
+	assert.InDelta(t, 1.0, result.Phases[1].TPS, 0.001)
+}
+
+func TestCollector_StreamPolling(t *testing.T) {
+	t.Parallel()
+
+	var (
+		numTxs    = 20
+		startTime = time.Now()
+		txs       = generateRandomData(t, numTxs)
+		sentTxs   = make(chan common.SentTx)
+
+		// The transactions are streamed faster than the blocks are polled
+		sendInterval = 10 * time.Millisecond
+		pollInterval = 50 * time.Millisecond
+
+		sent  atomic.Int64
+		polls atomic.Int64
+	)
+
+	// Each streamed transaction is committed in its own block right away
+	mockClient := &mockClient{
+		getBlockFn: func(_ context.Context, height *int64) (*core_types.ResultBlock, error) {
+			return &core_types.ResultBlock{
+				BlockMeta: &types.BlockMeta{
+					Header: types.Header{
+						Height: *height,
+						Time:   startTime.Add(time.Duration(*height) * time.Second),
+						NumTxs: 1,
+					},
+				},
+				Block: &types.Block{
+					Data: types.Data{
+						Txs: []types.Tx{
+							txs[*height-1],
+						},
+					},
+				},
+			}, nil
+		},
+		getLatestBlockHeightFn: func(_ context.Context) (int64, error) {
+			polls.Add(1)
+
+			return sent.Load(), nil
+		},
+		getBlockGasLimitFn: func(_ context.Context, _ int64) (int64, error) {
+			return 1000, nil
+		},
+		getBlockResultsFn: func(_ context.Context, _ *int64) (*core_types.ResultBlockResults, error) {
+			return newBlockResults(500), nil
+		},
+	}
+
+	streamPolls := make(chan int64, 1)
+
+	go func() {
+		for _, tx := range txs {
+			sentTxs <- common.SentTx{
+				Hash:   tmhash.Sum(tx),
+				SentAt: time.Now(),
+			}
+
+			sent.Add(1)
+
+			time.Sleep(sendInterval)
+		}
+
+		// Save the number of polls done while streaming
+		streamPolls <- polls.Load()
+
+		close(sentTxs)
+	}()
+
+	// Create the collector
+	c := NewCollector(context.Background(), mockClient, Config{
+		PollInterval:   pollInterval,
+		CollectTimeout: time.Minute * 5,
+	})
+
+	// Collect the results
+	result, err := c.StreamRunResult(sentTxs, 1, startTime, nil)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	// Make sure the blocks were polled while the transactions were streamed
+	assert.GreaterOrEqual(t, <-streamPolls, int64(2))
+
+	assert.Equal(t, numTxs, result.Outcomes.Succeeded)
+	assert.Len(t, result.Blocks, numTxs)
+}
+
+func TestCollector_StreamLatency(t *testing.T) {
+	t.Parallel()
+
+	var (
+		numTxs    = 40
+		startTime = time.Now()
+		txs       = generateRandomData(t, numTxs)
+		sentTxs   = make(chan common.SentTx)
+
+		// The stream lasts much longer than a single poll interval
+		sendInterval = 20 * time.Millisecond
+		pollInterval = 25 * time.Millisecond
+
+		sent atomic.Int64
+	)
+
+	// Each streamed transaction is committed in its own block right away
+	mockClient := &mockClient{
+		getBlockFn: func(_ context.Context, height *int64) (*core_types.ResultBlock, error) {
+			return &core_types.ResultBlock{
+				BlockMeta: &types.BlockMeta{
+					Header: types.Header{
+						Height: *height,
+						Time:   time.Now(),
+						NumTxs: 1,
+					},
+				},
+				Block: &types.Block{
+					Data: types.Data{
+						Txs: []types.Tx{
+							txs[*height-1],
+						},
+					},
+				},
+			}, nil
+		},
+		getLatestBlockHeightFn: func(_ context.Context) (int64, error) {
+			return sent.Load(), nil
+		},
+		getBlockGasLimitFn: func(_ context.Context, _ int64) (int64, error) {
+			return 1000, nil
+		},
+		getBlockResultsFn: func(_ context.Context, _ *int64) (*core_types.ResultBlockResults, error) {
+			return newBlockResults(500), nil
+		},
+	}
+
+	go func() {
+		defer close(sentTxs)
+
+		for _, tx := range txs {
+			sentTxs <- common.SentTx{
+				Hash:   tmhash.Sum(tx),
+				SentAt: time.Now(),
+			}
+
+			sent.Add(1)
+
+			time.Sleep(sendInterval)
+		}
+	}()
+
+	// Create the collector
+	c := NewCollector(context.Background(), mockClient, Config{
+		PollInterval:   pollInterval,
+		CollectTimeout: time.Minute * 5,
+	})
+
+	// Collect the results
+	result, err := c.StreamRunResult(sentTxs, 1, startTime, nil)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	require.Equal(t, numTxs, result.Outcomes.Succeeded)
+	require.NotNil(t, result.Latency)
+
+	// Make sure the observed latency is bounded by the poll interval,
+	// and not by the stream duration (over 30 poll intervals)
+	assert.Less(t, result.Latency.Observed.Max, 8*pollInterval)
+}
+
+func TestCollector_Interrupted(t *testing.T) {
+	t.Parallel()
+
+	var (
+		numTxs    = 10
+		committed = 4
+		startTime = time.Now()
+		txs       = generateRandomData(t, numTxs)
+		sentTxs   = make([]common.SentTx, numTxs)
+
+		ctx, cancelFn = context.WithCancel(context.Background())
+	)
+
+	defer cancelFn()
+
+	for i := 0; i < numTxs; i++ {
+		sentTxs[i] = common.SentTx{
+			Hash:   tmhash.Sum(txs[i]),
+			SentAt: startTime,
+		}
+	}
+
+	// Only the first few transactions are committed
+	// before the collection is interrupted
+	mockClient := &mockClient{
+		getBlockFn: func(_ context.Context, height *int64) (*core_types.ResultBlock, error) {
+			return &core_types.ResultBlock{
+				BlockMeta: &types.BlockMeta{
+					Header: types.Header{
+						Height: *height,
+						Time:   startTime.Add(time.Duration(*height) * time.Second),
+						NumTxs: 1,
+					},
+				},
+				Block: &types.Block{
+					Data: types.Data{
+						Txs: []types.Tx{
+							txs[*height-1],
+						},
+					},
+				},
+			}, nil
+		},
+		getLatestBlockHeightFn: func(ctx context.Context) (int64, error) {
+			if ctx.Err() != nil {
+				return 0, ctx.Err()
+			}
+
+			return int64(committed), nil
+		},
+		getBlockGasLimitFn: func(_ context.Context, height int64) (int64, error) {
+			if height == int64(committed) {
+				// Interrupt the collection once
+				// the last committed block is collected
+				cancelFn()
+			}
+
+			return 1000, nil
+		},
+		getBlockResultsFn: func(_ context.Context, _ *int64) (*core_types.ResultBlockResults, error) {
+			return newBlockResults(100), nil
+		},
+	}
+
+	// Create the collector
+	c := NewCollector(ctx, mockClient, Config{
+		CollectTimeout: time.Minute * 5,
+	})
+
+	// Collect the results
+	result, err := c.GetRunResult(sentTxs, 1, startTime)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	// Make sure the gathered results are kept
+	assert.True(t, result.Partial)
+	assert.Len(t, result.Blocks, committed)
+	assert.Len(t, result.Transactions, numTxs)
+
+	assert.Equal(t, numTxs, result.Outcomes.Sent)
+	assert.Equal(t, committed, result.Outcomes.Succeeded)
+	assert.Equal(t, numTxs-committed, result.Outcomes.NotIncluded)
+}
+
+func TestCollector_IdleBlocks(t *testing.T) {
+	t.Parallel()
+
+	var (
+		numTxs     = 4
+		committed  = 2
+		idleBlocks = uint64(3)
+		startTime  = time.Now()
+		txs        = generateRandomData(t, numTxs)
+		sentTxs    = make([]common.SentTx, numTxs)
+
+		latest = int64(0)
+	)
+
+	for i := 0; i < numTxs; i++ {
+		sentTxs[i] = common.SentTx{
+			Hash:   tmhash.Sum(txs[i]),
+			SentAt: startTime,
+		}
+	}
+
+	// A new block is created for every poll, but only
+	// the first few blocks contain the run transactions
+	mockClient := &mockClient{
+		getBlockFn: func(_ context.Context, height *int64) (*core_types.ResultBlock, error) {
+			blockTxs := []types.Tx{}
+			if *height <= int64(committed) {
+				blockTxs = append(blockTxs, txs[*height-1])
+			}
+
+			return &core_types.ResultBlock{
+				BlockMeta: &types.BlockMeta{
+					Header: types.Header{
+						Height: *height,
+						Time:   startTime.Add(time.Duration(*height) * time.Second),
+						NumTxs: int64(len(blockTxs)),
+					},
+				},
+				Block: &types.Block{
+					Data: types.Data{
+						Txs: blockTxs,
+					},
+				},
+			}, nil
+		},
+		getLatestBlockHeightFn: func(_ context.Context) (int64, error) {
+			latest++
+
+			return latest, nil
+		},
+		getBlockGasLimitFn: func(_ context.Context, _ int64) (int64, error) {
+			return 1000, nil
+		},
+		getBlockResultsFn: func(_ context.Context, _ *int64) (*core_types.ResultBlockResults, error) {
+			return newBlockResults(100), nil
+		},
+	}
+
+	// Create the collector
+	c := NewCollector(context.Background(), mockClient, Config{
+		CollectTimeout: time.Minute * 5,
+		IdleBlocks:     idleBlocks,
+	})
+
+	// Collect the results
+	result, err := c.GetRunResult(sentTxs, 1, startTime)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	// Make sure the collection stopped once it went idle
+	assert.True(t, result.Partial)
+	assert.Equal(t, int64(committed)+int64(idleBlocks), latest)
+
+	assert.Equal(t, committed, result.Outcomes.Succeeded)
+	assert.Equal(t, numTxs-committed, result.Outcomes.NotIncluded)
+}
+
+func TestCollector_Outcomes(t *testing.T) {
+	t.Parallel()
+
+	var (
+		startTime = time.Now()
+		txs       = generateRandomData(t, 4)
+
+		endpoints = []string{"http://node-1:26657", "http://node-2:26657"}
+		txTypes   = []string{"PACKAGE_DEPLOYMENT", "REALM_CALL"}
+
+		// The first two txs are included in the block (one of them fails),
+		// the third one is rejected, and the last one is never included.
+		// The txs are spread across two endpoints, and are of two types.
+		// The deployments carry generated payloads of different sizes,
+		// and the first call carries multiple messages
+		sentTxs = []common.SentTx{
+			{Hash: tmhash.Sum(txs[0]), SentAt: startTime, Endpoint: endpoints[0], Type: txTypes[1], Msgs: 10},
+			{
+				Hash:     tmhash.Sum(txs[1]),
+				SentAt:   startTime,
+				Endpoint: endpoints[1],
+				Type:     txTypes[0],
+				Payload:  2048,
+			},
+			{
+				Hash:     tmhash.Sum(txs[2]),
+				SentAt:   startTime,
+				Endpoint: endpoints[0],
+				Type:     txTypes[0],
+				Payload:  1024,
+				Err:      std.InvalidSequenceError{},
+			},
+			{Hash: tmhash.Sum(txs[3]), SentAt: startTime, Endpoint: endpoints[1], Type: txTypes[1]},
+		}
+	)
+
+	mockClient := &mockClient{
+		getBlockFn: func(_ context.Context, height *int64) (*core_types.ResultBlock, error) {
+			return &core_types.ResultBlock{
+				BlockMeta: &types.BlockMeta{
+					Header: types.Header{
+						Height: *height,
+						Time:   startTime.Add(time.Second),
+						NumTxs: 2,
+					},
+				},
+				Block: &types.Block{
+					Data: types.Data{
+						Txs: []types.Tx{txs[0], txs[1]},
+					},
+				},
+			}, nil
+		},
+		getLatestBlockHeightFn: func(_ context.Context) (int64, error) {
+			return 1, nil
+		},
+		getBlockResultsFn: func(_ context.Context, _ *int64) (*core_types.ResultBlockResults, error) {
+			return &core_types.ResultBlockResults{
+				Results: &state.ABCIResponses{
+					DeliverTxs: []abci.ResponseDeliverTx{
+						{
+							GasUsed: 100,
+						},
+						{
+							ResponseBase: abci.ResponseBase{
+								Error: std.OutOfGasError{},
+							},
+							GasUsed: 200,
+						},
+					},
+				},
+			}, nil
+		},
+	}
+
+	// Create the collector
+	c := NewCollector(context.Background(), mockClient, Config{
+		CollectTimeout: time.Millisecond * 100,
+	})
+
+	// Collect the results
+	result, err := c.GetRunResult(sentTxs, 1, startTime)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	// Make sure the outcomes are valid
+	assert.Equal(t, 4, result.Outcomes.Sent)
+	assert.Equal(t, 1, result.Outcomes.Succeeded)
+	assert.Equal(t, 1, result.Outcomes.Failed)
+	assert.Equal(t, 1, result.Outcomes.Rejected)
+	assert.Equal(t, 1, result.Outcomes.NotIncluded)
+
+	// The collection timed out before all txs were committed
+	assert.True(t, result.Partial)
+
+	assert.Equal(
+		t,
+		map[TxStatus]map[string]int{
+			Failed:   {"std.OutOfGasError": 1},
+			Rejected: {"std.InvalidSequenceError": 1},
+		},
+		result.Outcomes.Errors,
+	)
+
+	// Make sure the transaction results are valid
+	require.Len(t, result.Transactions, len(sentTxs))
+
+	assert.Equal(t, Succeeded, result.Transactions[0].Status)
+	assert.Equal(t, int64(100), result.Transactions[0].GasUsed)
+	assert.Equal(t, Failed, result.Transactions[1].Status)
+	assert.Equal(t, int64(200), result.Transactions[1].GasUsed)
+
+	// Make sure the endpoint breakdown is valid
+	require.Len(t, result.Endpoints, len(endpoints))
+
+	for index, endpoint := range result.Endpoints {
+		assert.Equal(t, endpoints[index], endpoint.Endpoint)
+		assert.Equal(t, 2, endpoint.Outcomes.Sent)
+		assert.Equal(t, time.Second, endpoint.Latency.Commit.Max)
+	}
+
+	assert.Equal(t, 1, result.Endpoints[0].Outcomes.Succeeded)
+	assert.Equal(t, 1, result.Endpoints[0].Outcomes.Rejected)
+	assert.Equal(t, 1, result.Endpoints[1].Outcomes.Failed)
+	assert.Equal(t, 1, result.Endpoints[1].Outcomes.NotIncluded)
+
+	// Make sure the type breakdown is valid
+	require.Len(t, result.Types, len(txTypes))
+
+	for index, typeResult := range result.Types {
+		assert.Equal(t, txTypes[index], typeResult.Type)
+		assert.Equal(t, 2, typeResult.Outcomes.Sent)
+	}
+
+	assert.Equal(t, 1, result.Types[0].Outcomes.Failed)
+	assert.Equal(t, 1, result.Types[0].Outcomes.Rejected)
+	assert.Equal(t, int64(200), result.Types[0].GasUsed)
+	assert.Equal(t, int64(200), result.Types[0].AverageGasUsed)
+
+	assert.Equal(t, 1, result.Types[1].Outcomes.Succeeded)
+	assert.Equal(t, 1, result.Types[1].Outcomes.NotIncluded)
+	assert.Equal(t, int64(100), result.Types[1].GasUsed)
+	assert.Equal(t, int64(100), result.Types[1].AverageGasUsed)
+
+	// Make sure the payload breakdown is valid, and ordered by size
+	require.Len(t, result.Payloads, 2)
+
+	assert.Equal(t, 1024, result.Payloads[0].Payload)
+	assert.Equal(t, 1, result.Payloads[0].Outcomes.Rejected)
+	assert.Equal(t, int64(0), result.Payloads[0].GasUsed)
+
+	assert.Equal(t, 2048, result.Payloads[1].Payload)
+	assert.Equal(t, 1, result.Payloads[1].Outcomes.Failed)
+	assert.Equal(t, int64(200), result.Payloads[1].AverageGasUsed)
+
+	// Make sure the message throughput accounts for
+	// the multi-message tx (10 + 1 messages in 2 txs)
+	require.Positive(t, result.AverageTPS)
+	assert.InDelta(t, 5.5, result.AverageMPS/result.AverageTPS, 0.1)
 }
@@ -0,0 +1,46 @@
+package collector
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// CalculateLatency calculates the latency percentiles
+// for the given latencies (ex. transaction or query latencies)
+func CalculateLatency(latencies []time.Duration) *LatencyResult {
+	if len(latencies) == 0 {
+		return &LatencyResult{}
+	}
+
+	sorted := make([]time.Duration, len(latencies))
+	copy(sorted, latencies)
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	var total time.Duration
+	for _, latency := range sorted {
+		total += latency
+	}
+
+	return &LatencyResult{
+		Average: total / time.Duration(len(sorted)),
+		P50:     percentile(sorted, 50),
+		P90:     percentile(sorted, 90),
+		P99:     percentile(sorted, 99),
+		Max:     sorted[len(sorted)-1],
+	}
+}
+
+// percentile returns the nearest-rank percentile
+// of the sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
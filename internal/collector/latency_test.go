@@ -0,0 +1,39 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatency_CalculateLatency(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no latencies", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, &LatencyResult{}, CalculateLatency(nil))
+	})
+
+	t.Run("valid percentiles", func(t *testing.T) {
+		t.Parallel()
+
+		// Latencies of 100ms, 99ms, ..., 1ms
+		latencies := make([]time.Duration, 0, 100)
+		for i := 100; i > 0; i-- {
+			latencies = append(latencies, time.Duration(i)*time.Millisecond)
+		}
+
+		result := CalculateLatency(latencies)
+
+		assert.Equal(t, 50*time.Millisecond, result.P50)
+		assert.Equal(t, 90*time.Millisecond, result.P90)
+		assert.Equal(t, 99*time.Millisecond, result.P99)
+		assert.Equal(t, 100*time.Millisecond, result.Max)
+		assert.Equal(t, 50500*time.Microsecond, result.Average)
+
+		// Make sure the original latencies are not modified
+		assert.Equal(t, 100*time.Millisecond, latencies[0])
+	})
+}
@@ -0,0 +1,69 @@
+package collector
+
+import (
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/supernova/internal/common"
+)
+
+// blockTx is a run transaction that
+// was committed in a block
+type blockTx struct {
+	deliverTx *abci.ResponseDeliverTx // the execution result, if any
+	sentTx    common.SentTx
+	index     int // the index of the transaction in the block
+}
+
+// txLookup is a simple lookup map for sent transactions
+type txLookup struct {
+	pending  map[string]common.SentTx // txs that are expected to be committed
+	rejected map[string]common.SentTx // txs that were rejected by the node
+}
+
+// newTxLookup creates a new instance of the tx lookup map
+func newTxLookup() *txLookup {
+	return &txLookup{
+		pending:  make(map[string]common.SentTx),
+		rejected: make(map[string]common.SentTx),
+	}
+}
+
+// add adds the sent transaction to the lookup map.
+// Rejected transactions are not expected to be committed
+func (t *txLookup) add(sentTx common.SentTx) {
+	key := string(sentTx.Hash)
+
+	if sentTx.Err != nil {
+		delete(t.pending, key)
+		t.rejected[key] = sentTx
+
+		return
+	}
+
+	t.pending[key] = sentTx
+}
+
+// size returns the number of transactions
+// that are expected to be committed
+func (t *txLookup) size() int {
+	return len(t.pending)
+}
+
+// belonging returns the transactions
+// that have been found in the lookup map
+func (t *txLookup) belonging(txs types.Txs) []*blockTx {
+	belong := make([]*blockTx, 0, len(txs))
+
+	for index, tx := range txs {
+		txHash := tx.Hash()
+
+		if sentTx, ok := t.pending[string(txHash)]; ok {
+			belong = append(belong, &blockTx{
+				sentTx: sentTx,
+				index:  index,
+			})
+		}
+	}
+
+	return belong
+}
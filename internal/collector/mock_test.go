@@ -4,18 +4,19 @@ import (
 	"context"
 
 	core_types "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/state"
 )
 
 type (
 	getBlockDelegate             func(ctx context.Context, height *int64) (*core_types.ResultBlock, error)
-	getBlockGasUsedDelegate      func(ctx context.Context, height int64) (int64, error)
+	getBlockResultsDelegate      func(ctx context.Context, height *int64) (*core_types.ResultBlockResults, error)
 	getBlockGasLimitDelegate     func(ctx context.Context, height int64) (int64, error)
 	getLatestBlockHeightDelegate func(ctx context.Context) (int64, error)
 )
 
 type mockClient struct {
 	getBlockFn             getBlockDelegate
-	getBlockGasUsedFn      getBlockGasUsedDelegate
+	getBlockResultsFn      getBlockResultsDelegate
 	getBlockGasLimitFn     getBlockGasLimitDelegate
 	getLatestBlockHeightFn getLatestBlockHeightDelegate
 }
@@ -28,12 +29,14 @@ func (m *mockClient) GetBlock(ctx context.Context, height *int64) (*core_types.R
 	return nil, nil
 }
 
-func (m *mockClient) GetBlockGasUsed(ctx context.Context, height int64) (int64, error) {
-	if m.getBlockGasUsedFn != nil {
-		return m.getBlockGasUsedFn(ctx, height)
+func (m *mockClient) GetBlockResults(ctx context.Context, height *int64) (*core_types.ResultBlockResults, error) {
+	if m.getBlockResultsFn != nil {
+		return m.getBlockResultsFn(ctx, height)
 	}
 
-	return 0, nil
+	return &core_types.ResultBlockResults{
+		Results: &state.ABCIResponses{},
+	}, nil
 }
 
 func (m *mockClient) GetBlockGasLimit(ctx context.Context, height int64) (int64, error) {
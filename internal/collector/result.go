@@ -0,0 +1,426 @@
+package collector
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gnolang/supernova/internal/common"
+	"github.com/gnolang/supernova/internal/profile"
+)
+
+// runCollection accumulates the
+// run results as blocks are collected
+type runCollection struct {
+	startTime time.Time
+	profile   profile.Profile
+
+	blocks    []*BlockResult
+	blockTxs  []int               // the number of run txs, per block
+	txs       []*TxResult         // the included txs
+	included  map[string]struct{} // the hashes of included txs
+	committed []int               // the number of included txs, per phase
+}
+
+// newRunCollection creates a new run collection.
+// The load profile is optional, and only present for rate-controlled runs
+func newRunCollection(startTime time.Time, loadProfile profile.Profile) *runCollection {
+	return &runCollection{
+		startTime: startTime,
+		profile:   loadProfile,
+		blocks:    make([]*BlockResult, 0),
+		blockTxs:  make([]int, 0),
+		txs:       make([]*TxResult, 0),
+		included:  make(map[string]struct{}),
+		committed: make([]int, len(loadProfile)),
+	}
+}
+
+// addBlock adds the block result, along with the run transactions
+// committed in the block, and the time the block was observed
+func (r *runCollection) addBlock(block *BlockResult, txs []*blockTx, observedAt time.Time) {
+	r.blocks = append(r.blocks, block)
+	r.blockTxs = append(r.blockTxs, len(txs))
+
+	for _, tx := range txs {
+		if tx.sentTx.Phase < len(r.committed) {
+			r.committed[tx.sentTx.Phase]++
+		}
+
+		txResult := &TxResult{
+			Hash:        hex.EncodeToString(tx.sentTx.Hash),
+			Status:      Succeeded,
+			Block:       block.Number,
+			SentAt:      tx.sentTx.SentAt,
+			CommittedAt: block.Time,
+			ObservedAt:  observedAt,
+			Endpoint:    tx.sentTx.Endpoint,
+			Type:        tx.sentTx.Type,
+			Payload:     tx.sentTx.Payload,
+			Msgs:        tx.sentTx.Msgs,
+		}
+
+		if tx.deliverTx != nil {
+			txResult.GasUsed = tx.deliverTx.GasUsed
+
+			if tx.deliverTx.IsErr() {
+				txResult.Status = Failed
+				txResult.Error = errorType(tx.deliverTx.Error)
+			}
+		}
+
+		r.included[string(tx.sentTx.Hash)] = struct{}{}
+		r.txs = append(r.txs, txResult)
+	}
+}
+
+// getRunResult generates the run result
+// for the collected transactions
+func (r *runCollection) getRunResult(txMap *txLookup) *RunResult {
+	txs := r.getTxResults(txMap)
+
+	return &RunResult{
+		Latency:      getLatencyStats(r.txs),
+		Outcomes:     getOutcomes(txs),
+		Blocks:       r.blocks,
+		Phases:       r.getPhaseResults(txMap),
+		Endpoints:    getEndpointResults(txs),
+		Types:        getTypeResults(txs),
+		Payloads:     getPayloadResults(txs),
+		Transactions: txs,
+		AverageTPS: calculateTPS(
+			r.startTime,
+			len(r.txs),
+		),
+		AverageMPS: calculateTPS(
+			r.startTime,
+			countMsgs(r.txs),
+		),
+	}
+}
+
+// getTxResults returns the results of all run transactions,
+// including the ones that were never included in a block
+func (r *runCollection) getTxResults(txMap *txLookup) []*TxResult {
+	txs := make([]*TxResult, 0, len(r.txs)+len(txMap.rejected))
+	txs = append(txs, r.txs...)
+
+	for _, sentTx := range txMap.rejected {
+		txs = append(txs, &TxResult{
+			Hash:     hex.EncodeToString(sentTx.Hash),
+			Status:   Rejected,
+			Error:    errorType(sentTx.Err),
+			SentAt:   sentTx.SentAt,
+			Endpoint: sentTx.Endpoint,
+			Type:     sentTx.Type,
+			Payload:  sentTx.Payload,
+			Msgs:     sentTx.Msgs,
+		})
+	}
+
+	for hash, sentTx := range txMap.pending {
+		if _, ok := r.included[hash]; ok {
+			continue
+		}
+
+		txs = append(txs, &TxResult{
+			Hash:     hex.EncodeToString(sentTx.Hash),
+			Status:   NotIncluded,
+			SentAt:   sentTx.SentAt,
+			Endpoint: sentTx.Endpoint,
+			Type:     sentTx.Type,
+			Payload:  sentTx.Payload,
+			Msgs:     sentTx.Msgs,
+		})
+	}
+
+	return txs
+}
+
+// countMsgs returns the total number of messages in the transactions.
+// Transactions without a message count hold a single message
+func countMsgs(txs []*TxResult) int {
+	msgs := 0
+
+	for _, tx := range txs {
+		msgs += max(tx.Msgs, 1)
+	}
+
+	return msgs
+}
+
+// getOutcomes generates the outcome
+// breakdown for the run transactions
+func getOutcomes(txs []*TxResult) *OutcomeResult {
+	outcomes := &OutcomeResult{
+		Sent:   len(txs),
+		Errors: make(map[TxStatus]map[string]int),
+	}
+
+	for _, tx := range txs {
+		switch tx.Status {
+		case Succeeded:
+			outcomes.Succeeded++
+		case Failed:
+			outcomes.Failed++
+		case Rejected:
+			outcomes.Rejected++
+		case NotIncluded:
+			outcomes.NotIncluded++
+		}
+
+		if tx.Error == "" {
+			continue
+		}
+
+		if _, ok := outcomes.Errors[tx.Status]; !ok {
+			outcomes.Errors[tx.Status] = make(map[string]int)
+		}
+
+		outcomes.Errors[tx.Status][tx.Error]++
+	}
+
+	return outcomes
+}
+
+// getLatencyStats generates the latency stats
+// for the included transactions
+func getLatencyStats(txs []*TxResult) *LatencyStats {
+	var (
+		commit   = make([]time.Duration, 0, len(txs))
+		observed = make([]time.Duration, 0, len(txs))
+	)
+
+	for _, tx := range txs {
+		if tx.Status != Succeeded && tx.Status != Failed {
+			// The tx was never included in a block
+			continue
+		}
+
+		commit = append(commit, tx.CommittedAt.Sub(tx.SentAt))
+		observed = append(observed, tx.ObservedAt.Sub(tx.SentAt))
+	}
+
+	return &LatencyStats{
+		Commit:   CalculateLatency(commit),
+		Observed: CalculateLatency(observed),
+	}
+}
+
+// getEndpointResults generates the result breakdown for each endpoint
+// the run transactions were sent to. The breakdown is only
+// present if the transactions were spread across several endpoints
+func getEndpointResults(txs []*TxResult) []*EndpointResult {
+	endpoints, endpointTxs := groupTxResults(txs, func(tx *TxResult) string {
+		return tx.Endpoint
+	})
+
+	if len(endpoints) < 2 {
+		return nil
+	}
+
+	results := make([]*EndpointResult, 0, len(endpoints))
+
+	for _, endpoint := range endpoints {
+		results = append(results, &EndpointResult{
+			Endpoint: endpoint,
+			Outcomes: getOutcomes(endpointTxs[endpoint]),
+			Latency:  getLatencyStats(endpointTxs[endpoint]),
+		})
+	}
+
+	return results
+}
+
+// getTypeResults generates the result breakdown for each transaction type.
+// The breakdown is only present if the run mixed several transaction types
+func getTypeResults(txs []*TxResult) []*TypeResult {
+	txTypes, typeTxs := groupTxResults(txs, func(tx *TxResult) string {
+		return tx.Type
+	})
+
+	if len(txTypes) < 2 {
+		return nil
+	}
+
+	results := make([]*TypeResult, 0, len(txTypes))
+
+	for _, txType := range txTypes {
+		gasUsed, averageGasUsed := getGasUsed(typeTxs[txType])
+
+		results = append(results, &TypeResult{
+			Type:           txType,
+			Outcomes:       getOutcomes(typeTxs[txType]),
+			Latency:        getLatencyStats(typeTxs[txType]),
+			GasUsed:        gasUsed,
+			AverageGasUsed: averageGasUsed,
+		})
+	}
+
+	return results
+}
+
+// getPayloadResults generates the result breakdown for each generated payload size,
+// ordered by size. The breakdown is only present if the run deployed generated payloads
+func getPayloadResults(txs []*TxResult) []*PayloadResult {
+	payloadTxs := make(map[int][]*TxResult)
+
+	for _, tx := range txs {
+		if tx.Payload == 0 {
+			continue
+		}
+
+		payloadTxs[tx.Payload] = append(payloadTxs[tx.Payload], tx)
+	}
+
+	if len(payloadTxs) == 0 {
+		return nil
+	}
+
+	payloads := make([]int, 0, len(payloadTxs))
+	for payload := range payloadTxs {
+		payloads = append(payloads, payload)
+	}
+
+	sort.Ints(payloads)
+
+	results := make([]*PayloadResult, 0, len(payloads))
+
+	for _, payload := range payloads {
+		gasUsed, averageGasUsed := getGasUsed(payloadTxs[payload])
+
+		results = append(results, &PayloadResult{
+			Payload:        payload,
+			Outcomes:       getOutcomes(payloadTxs[payload]),
+			Latency:        getLatencyStats(payloadTxs[payload]),
+			GasUsed:        gasUsed,
+			AverageGasUsed: averageGasUsed,
+		})
+	}
+
+	return results
+}
+
+// getGasUsed returns the total and the average
+// gas used by the included transactions
+func getGasUsed(txs []*TxResult) (int64, int64) {
+	var (
+		gasUsed  int64
+		included int64
+	)
+
+	for _, tx := range txs {
+		if tx.Status != Succeeded && tx.Status != Failed {
+			continue
+		}
+
+		gasUsed += tx.GasUsed
+		included++
+	}
+
+	if included == 0 {
+		return 0, 0
+	}
+
+	return gasUsed, gasUsed / included
+}
+
+// groupTxResults groups the transaction results by the given key.
+// The keys are returned sorted, for a stable result
+func groupTxResults(txs []*TxResult, keyFn func(*TxResult) string) ([]string, map[string][]*TxResult) {
+	groups := make(map[string][]*TxResult)
+
+	for _, tx := range txs {
+		key := keyFn(tx)
+
+		groups[key] = append(groups[key], tx)
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys, groups
+}
+
+// getPhaseResults generates the results for each load profile phase.
+// Blocks are attributed to the phase during which they were created,
+// and only the run txs in the blocks count towards the phase TPS
+func (r *runCollection) getPhaseResults(txMap *txLookup) []*PhaseResult {
+	if len(r.profile) == 0 {
+		return nil
+	}
+
+	var (
+		results   = make([]*PhaseResult, 0, len(r.profile))
+		phaseSent = make([]int, len(r.profile))
+		start     = r.startTime
+	)
+
+	for _, txs := range []map[string]common.SentTx{txMap.pending, txMap.rejected} {
+		for _, sentTx := range txs {
+			if sentTx.Phase < len(phaseSent) {
+				phaseSent[sentTx.Phase]++
+			}
+		}
+	}
+
+	for index, phase := range r.profile {
+		var (
+			end = start.Add(phase.Duration)
+
+			runTxs      int
+			blocks      int
+			utilization float64
+		)
+
+		for blockIndex, block := range r.blocks {
+			if block.Time.Before(start) || !block.Time.Before(end) {
+				continue
+			}
+
+			blocks++
+			runTxs += r.blockTxs[blockIndex]
+
+			if block.GasLimit > 0 {
+				utilization += float64(block.GasUsed) / float64(block.GasLimit)
+			}
+		}
+
+		if blocks > 0 {
+			utilization = utilization / float64(blocks) * 100
+		}
+
+		results = append(results, &PhaseResult{
+			Name:               phase.Name,
+			Shape:              string(phase.Shape),
+			StartRate:          phase.From,
+			EndRate:            phase.To,
+			Start:              start,
+			End:                end,
+			Sent:               phaseSent[index],
+			Committed:          r.committed[index],
+			Blocks:             blocks,
+			TPS:                float64(runTxs) / phase.Duration.Seconds(),
+			AverageUtilization: utilization,
+		})
+
+		start = end
+	}
+
+	return results
+}
+
+// errorType returns the type name of the transaction error,
+// which is used for grouping errors of the same kind (ex. std.InsufficientFeeError)
+func errorType(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%T", err)
+}
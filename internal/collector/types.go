@@ -9,15 +9,112 @@ import (
 
 type Client interface {
 	GetBlock(ctx context.Context, height *int64) (*core_types.ResultBlock, error)
-	GetBlockGasUsed(ctx context.Context, height int64) (int64, error)
+	GetBlockResults(ctx context.Context, height *int64) (*core_types.ResultBlockResults, error)
 	GetBlockGasLimit(ctx context.Context, height int64) (int64, error)
 	GetLatestBlockHeight(ctx context.Context) (int64, error)
 }
 
+// Config is the collector configuration
+type Config struct {
+	PollInterval   time.Duration // the interval in which the latest block height is polled
+	CollectTimeout time.Duration // the maximum collection time, once all txs are sent out
+	IdleBlocks     uint64        // the number of consecutive blocks without run txs that end the collection, if any
+}
+
 // RunResult is the complete test-run result
 type RunResult struct {
-	Blocks     []*BlockResult `json:"blocks"`
-	AverageTPS float64        `json:"averageTPS"`
+	Latency      *LatencyStats     `json:"latency"`
+	Outcomes     *OutcomeResult    `json:"outcomes"`
+	Blocks       []*BlockResult    `json:"blocks"`
+	Phases       []*PhaseResult    `json:"phases,omitempty"`
+	Endpoints    []*EndpointResult `json:"endpoints,omitempty"`
+	Types        []*TypeResult     `json:"types,omitempty"`
+	Payloads     []*PayloadResult  `json:"payloads,omitempty"`
+	Transactions []*TxResult       `json:"transactions"`
+	AverageTPS   float64           `json:"averageTPS"`
+	AverageMPS   float64           `json:"averageMPS"`        // the average committed messages per second
+	Partial      bool              `json:"partial,omitempty"` // the collection ended before all txs were committed
+}
+
+// TxStatus is the outcome of a single run transaction
+type TxStatus string
+
+const (
+	Succeeded   TxStatus = "succeeded"    // the tx was included in a block, and executed successfully
+	Failed      TxStatus = "failed"       // the tx was included in a block, but its execution failed (DeliverTx)
+	Rejected    TxStatus = "rejected"     // the tx was rejected by the node when sent out (CheckTx)
+	NotIncluded TxStatus = "not_included" // the tx was never included in a block
+)
+
+// TxResult is the single transaction run result
+type TxResult struct {
+	SentAt      time.Time `json:"sentAt"`
+	CommittedAt time.Time `json:"committedAt"` // the header time of the commit block
+	ObservedAt  time.Time `json:"observedAt"`  // the time the commit block was observed by the collector
+	Hash        string    `json:"hash"`
+	Status      TxStatus  `json:"status"`
+	Error       string    `json:"error,omitempty"`    // the type of the tx error, if any
+	Endpoint    string    `json:"endpoint,omitempty"` // the endpoint the tx was sent to
+	Type        string    `json:"type,omitempty"`     // the type of the tx (runtime)
+	Payload     int       `json:"payload,omitempty"`  // the size (bytes) of the generated payload deployed by the tx
+	Msgs        int       `json:"msgs,omitempty"`     // the number of messages in the tx
+	Block       int64     `json:"blockNumber,omitempty"`
+	GasUsed     int64     `json:"gasUsed,omitempty"`
+}
+
+// OutcomeResult is the outcome breakdown
+// of all run transactions
+type OutcomeResult struct {
+	Errors      map[TxStatus]map[string]int `json:"errors"` // the number of errors per type, for each outcome
+	Sent        int                         `json:"sent"`
+	Succeeded   int                         `json:"succeeded"`
+	Failed      int                         `json:"failed"`
+	Rejected    int                         `json:"rejected"`
+	NotIncluded int                         `json:"notIncluded"`
+}
+
+// EndpointResult is the result breakdown
+// of a single endpoint txs were sent to
+type EndpointResult struct {
+	Latency  *LatencyStats  `json:"latency"`
+	Outcomes *OutcomeResult `json:"outcomes"`
+	Endpoint string         `json:"endpoint"`
+}
+
+// TypeResult is the result breakdown
+// of a single transaction type (runtime)
+type TypeResult struct {
+	Latency        *LatencyStats  `json:"latency"`
+	Outcomes       *OutcomeResult `json:"outcomes"`
+	Type           string         `json:"type"`
+	GasUsed        int64          `json:"gasUsed"`        // the total gas used by the included txs
+	AverageGasUsed int64          `json:"averageGasUsed"` // the average gas used by an included tx
+}
+
+// PayloadResult is the result breakdown of a single generated payload size,
+// which makes up the size-vs-gas and size-vs-latency curves of a payload sweep
+type PayloadResult struct {
+	Latency        *LatencyStats  `json:"latency"`
+	Outcomes       *OutcomeResult `json:"outcomes"`
+	Payload        int            `json:"payload"`        // the size (bytes) of the generated payload
+	GasUsed        int64          `json:"gasUsed"`        // the total gas used by the included txs
+	AverageGasUsed int64          `json:"averageGasUsed"` // the average gas used by an included tx
+}
+
+// LatencyStats are the end-to-end transaction latency stats.
+// Latencies are measured from the moment the transaction was sent out
+type LatencyStats struct {
+	Commit   *LatencyResult `json:"commit"`   // latency until the commit block header time
+	Observed *LatencyResult `json:"observed"` // latency until the commit block was observed
+}
+
+// LatencyResult contains the latency percentiles
+type LatencyResult struct {
+	Average time.Duration `json:"average"`
+	P50     time.Duration `json:"p50"`
+	P90     time.Duration `json:"p90"`
+	P99     time.Duration `json:"p99"`
+	Max     time.Duration `json:"max"`
 }
 
 // BlockResult is the single-block test run result
@@ -28,3 +125,18 @@ type BlockResult struct {
 	GasUsed      int64     `json:"gasUsed"`
 	GasLimit     int64     `json:"gasLimit"`
 }
+
+// PhaseResult is the single load profile phase result
+type PhaseResult struct {
+	Start              time.Time `json:"start"`
+	End                time.Time `json:"end"`
+	Name               string    `json:"name"`
+	Shape              string    `json:"shape"`
+	StartRate          uint64    `json:"startRate"`
+	EndRate            uint64    `json:"endRate"`
+	Sent               int       `json:"sentTransactions"`
+	Committed          int       `json:"committedTransactions"`
+	Blocks             int       `json:"numBlocks"`
+	TPS                float64   `json:"tps"`
+	AverageUtilization float64   `json:"averageUtilization"`
+}
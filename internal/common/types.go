@@ -1,5 +1,7 @@
 package common
 
+import "time"
+
 // Batch is a common transaction batch
 type Batch interface {
 	// AddTxBroadcast adds the transaction broadcast to the batch
@@ -8,3 +10,23 @@ type Batch interface {
 	// Execute executes the batch send
 	Execute() ([]interface{}, error)
 }
+
+// TxLabel is the runtime label of a transaction,
+// set where the transaction is constructed
+type TxLabel struct {
+	Type    string // the type of the tx (runtime)
+	Payload int    // the size (bytes) of the generated payload deployed by the tx, if any
+}
+
+// SentTx is a transaction that was
+// sent out during the stress test run
+type SentTx struct {
+	SentAt   time.Time // the time the tx was sent out
+	Err      error     // the error returned by the node when sending the tx (CheckTx), if any
+	Endpoint string    // the endpoint the tx was sent to
+	Type     string    // the type of the tx (runtime)
+	Hash     []byte    // the hash of the transaction
+	Phase    int       // the index of the load profile phase the tx was sent in
+	Payload  int       // the size (bytes) of the generated payload deployed by the tx, if any
+	Msgs     int       // the number of messages in the tx
+}
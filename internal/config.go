@@ -2,9 +2,15 @@ package internal
 
 import (
 	"errors"
+	"fmt"
 	"regexp"
+	"strings"
+	"time"
 
 	"github.com/gnolang/gno/tm2/pkg/crypto/bip39"
+	"github.com/gnolang/supernova/internal/collector"
+	"github.com/gnolang/supernova/internal/profile"
+	"github.com/gnolang/supernova/internal/query"
 	"github.com/gnolang/supernova/internal/runtime"
 )
 
@@ -15,8 +21,36 @@ var (
 	errInvalidSubaccounts  = errors.New("invalid number of subaccounts specified")
 	errInvalidTransactions = errors.New("invalid number of transactions specified")
 	errInvalidBatchSize    = errors.New("invalid batch size specified")
+	errInvalidConcurrency  = errors.New("invalid send concurrency specified")
+	errMissingRate         = errors.New("time-bounded runs require a send rate")
+	errInvalidProfile      = errors.New("invalid load profile specified")
+	errInvalidRealm        = errors.New("invalid realm source specified")
+	errInvalidScript       = errors.New("invalid script source specified")
+	errInvalidMix          = errors.New("invalid runtime mix specified")
+	errInvalidTransfer     = errors.New("invalid transfer pattern specified")
+	errInvalidRealmPath    = errors.New("invalid realm path specified")
+	errInvalidRealmArgs    = errors.New("invalid realm call arguments specified")
+	errConflictingRealm    = errors.New("realm source and realm path are mutually exclusive")
+	errInvalidPayload      = errors.New("invalid payload size specified")
+	errInvalidPayloadFiles = errors.New("invalid number of payload files specified")
+	errInvalidStorage      = errors.New("invalid storage workload specified")
+	errInvalidQuery        = errors.New("invalid query load specified")
+	errMissingQueryRealm   = errors.New("query load requires a realm to query")
+	errMissingQueryTime    = errors.New("query stages require a duration")
+	errInvalidPollInterval = errors.New("invalid collector poll interval specified")
+	errInvalidCollectTime  = errors.New("invalid collector timeout specified")
+	errMissingHistory      = errors.New("history runs require a block range or a transaction file")
+	errConflictingHistory  = errors.New("history block range and history file are mutually exclusive")
+	errInvalidHistory      = errors.New("invalid history block range specified")
+	errInvalidHistoryURL   = errors.New("invalid history URL specified")
+	errInvalidSpeed        = errors.New("invalid history speed specified")
+	errHistoryLoad         = errors.New("history runs follow the source timing, and can't have their own load")
 )
 
+// QueryMode is the mode of the query-only stages,
+// which don't send out any transactions
+const QueryMode = "QUERY"
+
 var (
 	// httpRegex is used for verifying the cluster's JSON-RPC HTTP endpoint
 	httpRegex = regexp.MustCompile(`(https?://.*)(:(\d*)/?(.*))?`)
@@ -27,23 +61,182 @@ var (
 
 // Config is the central pipeline configuration
 type Config struct {
-	URL      string // the URL of the cluster
-	ChainID  string // the chain ID of the cluster
-	Mnemonic string // the mnemonic for the keyring
-	Mode     string // the stress test mode
-	Output   string // output path for results JSON, if any
+	URL      string `yaml:"url"`      // the URL of the cluster, or a comma separated list of endpoint URLs
+	ChainID  string `yaml:"chainID"`  // the chain ID of the cluster
+	Mnemonic string `yaml:"mnemonic"` // the mnemonic for the keyring
+	Mode     string `yaml:"mode"`     // the stress test mode
+	Output   string `yaml:"output"`   // output path for results JSON, if any
+	Realm    string `yaml:"realm"`    // the path to the custom realm file or directory, if any
+	Mix      string `yaml:"mix"`      // the weighted runtime mix of the MIXED mode, if any
+	Script   string `yaml:"script"`   // the path to the custom RUN script file or directory, if any
+
+	RealmPath string   `yaml:"realmPath"` // the path of the existing on-chain realm to call, if any
+	RealmFunc string   `yaml:"realmFunc"` // the called realm function, if any
+	RealmArgs []string `yaml:"realmArgs"` // the argument templates of the called realm function
+
+	TransferPattern string `yaml:"transferPattern"` // the pattern of the BANK_SEND transfers
+	TransferAmount  uint64 `yaml:"transferAmount"`  // the amount (ugnot) of a single BANK_SEND transfer
+
+	PayloadSize  string `yaml:"payloadSize"`  // the generated deployment payload size, or size sweep, if any
+	PayloadFiles uint64 `yaml:"payloadFiles"` // the number of files the generated payload is split into
+
+	StorageKeys      uint64 `yaml:"storageKeys"`      // the size of the REALM_STORAGE key space
+	StorageValueSize uint64 `yaml:"storageValueSize"` // the size (bytes) of the REALM_STORAGE values
+	StorageDeletes   uint64 `yaml:"storageDeletes"`   // the share (percentage) of REALM_STORAGE calls that delete a key
+	StoragePrice     uint64 `yaml:"storagePrice"`     // the storage deposit price (ugnot per byte) of the chain
+
+	QueryPath        string `yaml:"queryPath"`        // the ABCI path of the query load (vm/qrender or vm/qeval)
+	QueryRealm       string `yaml:"queryRealm"`       // the path of the queried realm, if not the deployed one
+	QueryExpr        string `yaml:"queryExpr"`        // the query template (render path or evaluated expression)
+	QueryConcurrency uint64 `yaml:"queryConcurrency"` // the number of concurrent query workers, if any
+	QueryRate        uint64 `yaml:"queryRate"`        // the total query rate cap (queries / s), if any
+
+	SubAccounts  uint64 `yaml:"subAccounts"`  // the number of sub-accounts in the run
+	Transactions uint64 `yaml:"transactions"` // the total number of transactions
+	MsgsPerTx    uint64 `yaml:"msgsPerTx"`    // the number of messages in a single transaction
+	BatchSize    uint64 `yaml:"batch"`        // the maximum size of the batch
+	Concurrency  uint64 `yaml:"concurrency"`  // the number of concurrent send lanes
 
-	SubAccounts  uint64 // the number of sub-accounts in the run
-	Transactions uint64 // the total number of transactions
-	BatchSize    uint64 // the maximum size of the batch
+	FundingMargin uint64 `yaml:"fundingMargin"` // the safety margin (percentage) added to the sub-account cost
+
+	HistoryURL    string  `yaml:"historyURL"`    // the source chain URL of the HISTORY mode, if not the cluster
+	HistoryBlocks string  `yaml:"historyBlocks"` // the source chain block range replayed by the HISTORY mode, if any
+	HistoryFile   string  `yaml:"historyFile"`   // the path to the tx export file replayed by the HISTORY mode, if any
+	HistorySpeed  float64 `yaml:"historySpeed"`  // the speed factor of the HISTORY replay timing
+
+	Rate     uint64        `yaml:"rate"`     // the target send rate (txs / s), if any
+	Duration time.Duration `yaml:"duration"` // the duration of a time-bounded run, if any
+	Profile  string        `yaml:"profile"`  // the load profile specification, if any
+
+	PollInterval   time.Duration `yaml:"pollInterval"`   // the interval in which the collector polls for new blocks
+	CollectTimeout time.Duration `yaml:"collectTimeout"` // the maximum collection time, once all txs are sent out
+	IdleBlocks     uint64        `yaml:"idleBlocks"`     // the number of blocks without run txs that end the collection
+
+	DryRun bool `yaml:"dryRun"` // flag indicating if only the execution plan is displayed, without broadcasting
+
+	Stages []Stage `yaml:"stages"` // the run stages, if any
+}
+
+// Stage is a single run stage, with its own runtime mode and load.
+// Stage values that are unset are inherited from the run configuration
+type Stage struct {
+	Name   string `yaml:"name"`   // the name of the stage
+	Mode   string `yaml:"mode"`   // the stress test mode
+	Realm  string `yaml:"realm"`  // the path to the custom realm file or directory, if any
+	Mix    string `yaml:"mix"`    // the weighted runtime mix of the MIXED mode, if any
+	Script string `yaml:"script"` // the path to the custom RUN script file or directory, if any
+
+	RealmPath string   `yaml:"realmPath"` // the path of the existing on-chain realm to call, if any
+	RealmFunc string   `yaml:"realmFunc"` // the called realm function, if any
+	RealmArgs []string `yaml:"realmArgs"` // the argument templates of the called realm function
+
+	TransferPattern string `yaml:"transferPattern"` // the pattern of the BANK_SEND transfers
+	TransferAmount  uint64 `yaml:"transferAmount"`  // the amount (ugnot) of a single BANK_SEND transfer
+
+	PayloadSize  string `yaml:"payloadSize"`  // the generated deployment payload size, or size sweep, if any
+	PayloadFiles uint64 `yaml:"payloadFiles"` // the number of files the generated payload is split into
+
+	StorageKeys      uint64 `yaml:"storageKeys"`      // the size of the REALM_STORAGE key space
+	StorageValueSize uint64 `yaml:"storageValueSize"` // the size (bytes) of the REALM_STORAGE values
+	StorageDeletes   uint64 `yaml:"storageDeletes"`   // the share (percentage) of REALM_STORAGE calls that delete a key
+	StoragePrice     uint64 `yaml:"storagePrice"`     // the storage deposit price (ugnot per byte) of the chain
+
+	QueryPath        string `yaml:"queryPath"`        // the ABCI path of the query load (vm/qrender or vm/qeval)
+	QueryRealm       string `yaml:"queryRealm"`       // the path of the queried realm, if not the deployed one
+	QueryExpr        string `yaml:"queryExpr"`        // the query template (render path or evaluated expression)
+	QueryConcurrency uint64 `yaml:"queryConcurrency"` // the number of concurrent query workers, if any
+	QueryRate        uint64 `yaml:"queryRate"`        // the total query rate cap (queries / s), if any
+
+	HistoryURL    string  `yaml:"historyURL"`    // the source chain URL of the HISTORY mode, if not the cluster
+	HistoryBlocks string  `yaml:"historyBlocks"` // the source chain block range replayed by the HISTORY mode, if any
+	HistoryFile   string  `yaml:"historyFile"`   // the path to the tx export file replayed by the HISTORY mode, if any
+	HistorySpeed  float64 `yaml:"historySpeed"`  // the speed factor of the HISTORY replay timing
+
+	Transactions uint64        `yaml:"transactions"` // the total number of transactions
+	MsgsPerTx    uint64        `yaml:"msgsPerTx"`    // the number of messages in a single transaction
+	Rate         uint64        `yaml:"rate"`         // the target send rate (txs / s), if any
+	Duration     time.Duration `yaml:"duration"`     // the duration of a time-bounded stage, if any
+	Profile      string        `yaml:"profile"`      // the load profile specification, if any
+
+	history *runtime.TxHistory // the source chain history replayed by HISTORY stages, once loaded
+	set     map[string]bool    // the keys set in the scenario file, so explicit zero values are not inherited
 }
 
 // Validate validates the stress-test configuration
 func (cfg *Config) Validate() error {
-	// Make sure the URL is valid
-	if !httpRegex.MatchString(cfg.URL) &&
-		!wsRegex.MatchString(cfg.URL) {
-		return errInvalidURL
+	// Make sure the accounts are valid
+	if err := cfg.ValidateAccounts(); err != nil {
+		return err
+	}
+
+	// Make sure the sending is valid
+	if err := cfg.validateSending(); err != nil {
+		return err
+	}
+
+	// Make sure the stages are valid
+	for _, stage := range cfg.stages() {
+		if err := stage.validate(); err != nil {
+			return fmt.Errorf("invalid stage %s, %w", stage.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// ValidateReplay validates the cluster endpoints and the sending
+// configuration, which is all the transaction replay requires
+func (cfg *Config) ValidateReplay() error {
+	if err := cfg.validateEndpoints(); err != nil {
+		return err
+	}
+
+	return cfg.validateSending()
+}
+
+// validateSending validates the batching, send
+// concurrency and result collection configuration
+func (cfg *Config) validateSending() error {
+	// Make sure the batch size is valid
+	if cfg.BatchSize < 1 {
+		return errInvalidBatchSize
+	}
+
+	// Make sure the send concurrency is valid
+	if cfg.Concurrency < 1 {
+		return errInvalidConcurrency
+	}
+
+	// Make sure the collector timings are valid
+	if cfg.PollInterval <= 0 {
+		return errInvalidPollInterval
+	}
+
+	if cfg.CollectTimeout <= 0 {
+		return errInvalidCollectTime
+	}
+
+	return nil
+}
+
+// validateEndpoints validates the cluster endpoint URLs
+func (cfg *Config) validateEndpoints() error {
+	for _, url := range cfg.endpoints() {
+		if !httpRegex.MatchString(url) &&
+			!wsRegex.MatchString(url) {
+			return errInvalidURL
+		}
+	}
+
+	return nil
+}
+
+// ValidateAccounts validates the cluster endpoints and the
+// account configuration, which is all the funds reclamation requires
+func (cfg *Config) ValidateAccounts() error {
+	// Make sure the URLs are valid
+	if err := cfg.validateEndpoints(); err != nil {
+		return err
 	}
 
 	// Make sure the mnemonic is valid
@@ -51,25 +244,520 @@ func (cfg *Config) Validate() error {
 		return errInvalidMnemonic
 	}
 
+	// Make sure the number of subaccounts is valid
+	if cfg.SubAccounts < 1 {
+		return errInvalidSubaccounts
+	}
+
+	return nil
+}
+
+// collectorConfig returns the configuration
+// of the stage result collectors
+func (cfg *Config) collectorConfig() collector.Config {
+	return collector.Config{
+		PollInterval:   cfg.PollInterval,
+		CollectTimeout: cfg.CollectTimeout,
+		IdleBlocks:     cfg.IdleBlocks,
+	}
+}
+
+// endpoints returns the URLs of the cluster endpoints.
+// The first endpoint is the primary one, which is used
+// for the run setup, funding and result collection
+func (cfg *Config) endpoints() []string {
+	urls := strings.Split(cfg.URL, ",")
+
+	for index, url := range urls {
+		urls[index] = strings.TrimSpace(url)
+	}
+
+	return urls
+}
+
+// stages returns the run stages, with the unset
+// stage values inherited from the run configuration.
+// A run without stages has a single stage
+func (cfg *Config) stages() []Stage {
+	if len(cfg.Stages) == 0 {
+		return []Stage{
+			{
+				Name:   cfg.Mode,
+				Mode:   cfg.Mode,
+				Realm:  cfg.Realm,
+				Mix:    cfg.Mix,
+				Script: cfg.Script,
+
+				RealmPath: cfg.RealmPath,
+				RealmFunc: cfg.RealmFunc,
+				RealmArgs: cfg.RealmArgs,
+
+				TransferPattern: cfg.TransferPattern,
+				TransferAmount:  cfg.TransferAmount,
+
+				PayloadSize:  cfg.PayloadSize,
+				PayloadFiles: cfg.PayloadFiles,
+
+				StorageKeys:      cfg.StorageKeys,
+				StorageValueSize: cfg.StorageValueSize,
+				StorageDeletes:   cfg.StorageDeletes,
+				StoragePrice:     cfg.StoragePrice,
+
+				QueryPath:        cfg.QueryPath,
+				QueryRealm:       cfg.QueryRealm,
+				QueryExpr:        cfg.QueryExpr,
+				QueryConcurrency: cfg.QueryConcurrency,
+				QueryRate:        cfg.QueryRate,
+
+				HistoryURL:    cfg.HistoryURL,
+				HistoryBlocks: cfg.HistoryBlocks,
+				HistoryFile:   cfg.HistoryFile,
+				HistorySpeed:  cfg.HistorySpeed,
+
+				Transactions: cfg.Transactions,
+				MsgsPerTx:    cfg.MsgsPerTx,
+				Rate:         cfg.Rate,
+				Duration:     cfg.Duration,
+				Profile:      cfg.Profile,
+			},
+		}
+	}
+
+	stages := make([]Stage, 0, len(cfg.Stages))
+
+	for index, stage := range cfg.Stages {
+		if stage.Name == "" {
+			stage.Name = fmt.Sprintf("stage-%d", index+1)
+		}
+
+		if stage.Mode == "" {
+			stage.Mode = cfg.Mode
+		}
+
+		if stage.Realm == "" {
+			stage.Realm = cfg.Realm
+		}
+
+		if stage.Mix == "" {
+			stage.Mix = cfg.Mix
+		}
+
+		if stage.Script == "" {
+			stage.Script = cfg.Script
+		}
+
+		if stage.RealmPath == "" {
+			stage.RealmPath = cfg.RealmPath
+		}
+
+		if stage.RealmFunc == "" {
+			stage.RealmFunc = cfg.RealmFunc
+		}
+
+		if stage.RealmArgs == nil {
+			stage.RealmArgs = cfg.RealmArgs
+		}
+
+		if stage.TransferPattern == "" {
+			stage.TransferPattern = cfg.TransferPattern
+		}
+
+		if !stage.isSet("transferAmount", stage.TransferAmount) {
+			stage.TransferAmount = cfg.TransferAmount
+		}
+
+		if stage.PayloadSize == "" {
+			stage.PayloadSize = cfg.PayloadSize
+		}
+
+		if stage.PayloadFiles == 0 {
+			stage.PayloadFiles = cfg.PayloadFiles
+		}
+
+		if stage.StorageKeys == 0 {
+			stage.StorageKeys = cfg.StorageKeys
+		}
+
+		if stage.StorageValueSize == 0 {
+			stage.StorageValueSize = cfg.StorageValueSize
+		}
+
+		if !stage.isSet("storageDeletes", stage.StorageDeletes) {
+			stage.StorageDeletes = cfg.StorageDeletes
+		}
+
+		if !stage.isSet("storagePrice", stage.StoragePrice) {
+			stage.StoragePrice = cfg.StoragePrice
+		}
+
+		if stage.QueryPath == "" {
+			stage.QueryPath = cfg.QueryPath
+		}
+
+		if stage.QueryRealm == "" {
+			stage.QueryRealm = cfg.QueryRealm
+		}
+
+		if stage.QueryExpr == "" {
+			stage.QueryExpr = cfg.QueryExpr
+		}
+
+		if !stage.isSet("queryConcurrency", stage.QueryConcurrency) {
+			stage.QueryConcurrency = cfg.QueryConcurrency
+		}
+
+		if !stage.isSet("queryRate", stage.QueryRate) {
+			stage.QueryRate = cfg.QueryRate
+		}
+
+		if stage.HistoryURL == "" {
+			stage.HistoryURL = cfg.HistoryURL
+		}
+
+		// The history source is inherited as a whole,
+		// since the block range and the file are exclusive
+		if stage.HistoryBlocks == "" && stage.HistoryFile == "" {
+			stage.HistoryBlocks = cfg.HistoryBlocks
+			stage.HistoryFile = cfg.HistoryFile
+		}
+
+		if stage.HistorySpeed == 0 {
+			stage.HistorySpeed = cfg.HistorySpeed
+		}
+
+		if stage.MsgsPerTx == 0 {
+			stage.MsgsPerTx = cfg.MsgsPerTx
+		}
+
+		// The load values are inherited only if the stage
+		// doesn't specify its own load
+		if stage.Transactions == 0 && stage.Rate == 0 &&
+			stage.Duration == 0 && stage.Profile == "" {
+			stage.Transactions = cfg.Transactions
+			stage.Rate = cfg.Rate
+			stage.Duration = cfg.Duration
+			stage.Profile = cfg.Profile
+		}
+
+		stages = append(stages, stage)
+	}
+
+	return stages
+}
+
+// isSet checks if the stage value with the given key was set explicitly.
+// Values where zero is valid (ex. no deletes) are only unset if they are missing
+func (s Stage) isSet(key string, value uint64) bool {
+	return value != 0 || s.set[key]
+}
+
+// validate validates the stage configuration
+func (s Stage) validate() error {
+	// Query stages don't send out transactions
+	if s.isQueryOnly() {
+		if s.Duration == 0 {
+			return errMissingQueryTime
+		}
+
+		if s.QueryRealm == "" {
+			return errMissingQueryRealm
+		}
+
+		return s.validateQuery()
+	}
+
 	// Make sure the mode is valid
-	if !runtime.IsRuntime(runtime.Type(cfg.Mode)) {
+	if !runtime.IsRuntime(runtime.Type(s.Mode)) {
 		return errInvalidMode
 	}
 
-	// Make sure the number of subaccounts is valid
-	if cfg.SubAccounts < 1 {
-		return errInvalidSubaccounts
+	// Make sure the accompanying query load is valid
+	if s.QueryConcurrency > 0 {
+		if err := s.validateQuery(); err != nil {
+			return err
+		}
+	}
+
+	// Make sure the runtime mix is valid
+	if runtime.Type(s.Mode) == runtime.Mixed {
+		if _, err := runtime.ParseMix(s.Mix); err != nil {
+			return fmt.Errorf("%w, %w", errInvalidMix, err)
+		}
+	}
+
+	// Make sure the transfer pattern is valid
+	if s.TransferPattern != "" && !runtime.IsTransferPattern(runtime.TransferPattern(s.TransferPattern)) {
+		return errInvalidTransfer
+	}
+
+	// Make sure the generated payload is valid
+	if s.PayloadSize != "" {
+		if _, err := runtime.ParsePayloadSizes(s.PayloadSize); err != nil {
+			return fmt.Errorf("%w, %w", errInvalidPayload, err)
+		}
+
+		if s.PayloadFiles < 1 {
+			return errInvalidPayloadFiles
+		}
+	}
+
+	// Make sure the storage workload is valid
+	if s.StorageDeletes > 100 {
+		return fmt.Errorf("%w, delete share %d%% exceeds 100%%", errInvalidStorage, s.StorageDeletes)
+	}
+
+	// Make sure the custom realm is present
+	if s.Realm != "" {
+		if _, err := loadRealm(s.Realm); err != nil {
+			return fmt.Errorf("%w, %w", errInvalidRealm, err)
+		}
+	}
+
+	// Make sure the custom script is present
+	if s.Script != "" {
+		if _, err := loadScript(s.Script); err != nil {
+			return fmt.Errorf("%w, %w", errInvalidScript, err)
+		}
+	}
+
+	// Make sure the called on-chain realm is valid
+	if s.RealmPath != "" {
+		if s.Realm != "" {
+			return errConflictingRealm
+		}
+
+		if !strings.HasPrefix(s.RealmPath, "gno.land/r/") {
+			return fmt.Errorf("%w, %q", errInvalidRealmPath, s.RealmPath)
+		}
+	}
+
+	// Make sure the realm call arguments are valid
+	if _, err := runtime.ParseTemplates(s.RealmArgs); err != nil {
+		return fmt.Errorf("%w, %w", errInvalidRealmArgs, err)
+	}
+
+	// Make sure the history source is valid.
+	// History stages send out the source transactions
+	if runtime.Type(s.Mode) == runtime.History {
+		return s.validateHistory()
+	}
+
+	// Make sure the load profile is valid
+	if s.Profile != "" {
+		if _, err := profile.Parse(s.Profile); err != nil {
+			return fmt.Errorf("%w, %w", errInvalidProfile, err)
+		}
+	}
+
+	// Make sure time-bounded runs have a send rate
+	if s.Profile == "" && s.Duration > 0 && s.Rate == 0 {
+		return errMissingRate
 	}
 
 	// Make sure the number of transactions is valid
-	if cfg.Transactions < 1 {
+	if s.totalTransactions() < 1 {
 		return errInvalidTransactions
 	}
 
-	// Make sure the batch size is valid
-	if cfg.BatchSize < 1 {
-		return errInvalidBatchSize
+	return nil
+}
+
+// validateHistory validates the history source and timing of the stage
+func (s Stage) validateHistory() error {
+	switch {
+	case s.HistoryBlocks != "" && s.HistoryFile != "":
+		return errConflictingHistory
+	case s.HistoryBlocks == "" && s.HistoryFile == "":
+		return errMissingHistory
+	case s.HistoryBlocks != "":
+		if _, _, err := parseBlockRange(s.HistoryBlocks); err != nil {
+			return fmt.Errorf("%w, %w", errInvalidHistory, err)
+		}
+	}
+
+	if s.HistoryURL != "" && !httpRegex.MatchString(s.HistoryURL) && !wsRegex.MatchString(s.HistoryURL) {
+		return errInvalidHistoryURL
+	}
+
+	if s.HistorySpeed <= 0 {
+		return errInvalidSpeed
+	}
+
+	if s.Rate > 0 || s.Duration > 0 || s.Profile != "" {
+		return errHistoryLoad
 	}
 
 	return nil
 }
+
+// validateQuery validates the query load configuration of the stage
+func (s Stage) validateQuery() error {
+	if s.QueryPath != "" && !query.IsQueryPath(s.QueryPath) {
+		return fmt.Errorf("%w, unsupported path %q", errInvalidQuery, s.QueryPath)
+	}
+
+	if s.QueryRealm != "" && !strings.HasPrefix(s.QueryRealm, "gno.land/") {
+		return fmt.Errorf("%w, %q", errInvalidRealmPath, s.QueryRealm)
+	}
+
+	if _, err := runtime.ParseTemplate(s.QueryExpr); err != nil {
+		return fmt.Errorf("%w, %w", errInvalidQuery, err)
+	}
+
+	return nil
+}
+
+// isQueryOnly returns a flag indicating if the stage
+// runs only the query load, without any transactions
+func (s Stage) isQueryOnly() bool {
+	return s.Mode == QueryMode
+}
+
+// queryConfig returns the query load configuration of the stage,
+// for the given queried realm
+func (s Stage) queryConfig(realm string) (query.Config, error) {
+	path := s.QueryPath
+	if path == "" {
+		path = query.RenderPath
+	}
+
+	expr := s.QueryExpr
+	if expr == "" && path == query.EvalPath {
+		// The realm is rendered by default
+		expr = `Render("")`
+	}
+
+	tmpl, err := runtime.ParseTemplate(expr)
+	if err != nil {
+		return query.Config{}, fmt.Errorf("unable to parse query template, %w", err)
+	}
+
+	// Render queries use <realm>:<path>,
+	// while evaluations use <realm>.<expression>
+	separator := ":"
+	if path == query.EvalPath {
+		separator = "."
+	}
+
+	return query.Config{
+		Path: path,
+		DataFn: func(index int) ([]byte, error) {
+			data, err := tmpl.Render("", index)
+			if err != nil {
+				return nil, fmt.Errorf("unable to render query, %w", err)
+			}
+
+			return []byte(realm + separator + data), nil
+		},
+		Concurrency: int(max(s.QueryConcurrency, 1)),
+		Rate:        s.QueryRate,
+	}, nil
+}
+
+// runtimeConfig returns the runtime configuration of the stage
+func (s Stage) runtimeConfig() (runtime.Config, error) {
+	cfg := runtime.Config{
+		TransferPattern: runtime.TransferPattern(s.TransferPattern),
+		TransferAmount:  int64(s.TransferAmount),
+
+		MsgsPerTx: s.MsgsPerTx,
+		History:   s.history,
+
+		RealmPath: s.RealmPath,
+		CallFunc:  s.RealmFunc,
+
+		Storage: runtime.Storage{
+			Keys:      s.StorageKeys,
+			ValueSize: s.StorageValueSize,
+			Deletes:   s.StorageDeletes,
+			Price:     s.StoragePrice,
+		},
+	}
+
+	if s.Realm != "" {
+		realm, err := loadRealm(s.Realm)
+		if err != nil {
+			return runtime.Config{}, fmt.Errorf("unable to load realm, %w", err)
+		}
+
+		cfg.Realm = realm
+	}
+
+	if s.Script != "" {
+		script, err := loadScript(s.Script)
+		if err != nil {
+			return runtime.Config{}, fmt.Errorf("unable to load script, %w", err)
+		}
+
+		cfg.Script = script
+	}
+
+	if s.PayloadSize != "" {
+		sizes, err := runtime.ParsePayloadSizes(s.PayloadSize)
+		if err != nil {
+			return runtime.Config{}, fmt.Errorf("unable to parse payload size, %w", err)
+		}
+
+		cfg.Payload = &runtime.Payload{
+			Sizes: sizes,
+			Files: s.PayloadFiles,
+		}
+	}
+
+	args, err := runtime.ParseTemplates(s.RealmArgs)
+	if err != nil {
+		return runtime.Config{}, fmt.Errorf("unable to parse realm call arguments, %w", err)
+	}
+
+	cfg.CallArgs = args
+
+	if runtime.Type(s.Mode) == runtime.Mixed {
+		mix, err := runtime.ParseMix(s.Mix)
+		if err != nil {
+			return runtime.Config{}, fmt.Errorf("unable to parse runtime mix, %w", err)
+		}
+
+		cfg.Mix = mix
+	}
+
+	return cfg, nil
+}
+
+// isRateControlled returns a flag indicating if the stage transactions
+// are sent out following a load profile, instead of in a single burst
+func (s Stage) isRateControlled() bool {
+	return s.Profile != "" || s.Rate > 0
+}
+
+// loadProfile returns the load profile of a rate-controlled stage
+func (s Stage) loadProfile() (profile.Profile, error) {
+	switch {
+	case s.Profile != "":
+		return profile.Parse(s.Profile)
+	case s.Duration > 0:
+		return profile.NewConstant(s.Rate, s.Duration), nil
+	default:
+		// The transactions are sent out at
+		// a constant rate, until they run out
+		duration := time.Duration(
+			float64(s.Transactions) / float64(s.Rate) * float64(time.Second),
+		)
+
+		return profile.NewConstant(s.Rate, duration), nil
+	}
+}
+
+// totalTransactions returns the total number of transactions
+// that are expected to be sent out during the stage
+func (s Stage) totalTransactions() uint64 {
+	if s.Profile == "" && s.Duration == 0 {
+		return s.Transactions
+	}
+
+	loadProfile, err := s.loadProfile()
+	if err != nil {
+		return 0
+	}
+
+	return loadProfile.Total()
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"sync"
+	"time"
 
 	"github.com/gnolang/gno/gno.land/pkg/gnoland"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
@@ -15,11 +17,35 @@ import (
 	"github.com/schollz/progressbar/v3"
 )
 
-var errInsufficientFunds = errors.New("insufficient distributor funds")
+const (
+	// defaultTransferGas is the gas of a single transfer,
+	// assumed for the fee of the transfer gas estimation
+	defaultTransferGas = 100_000
+
+	// transferGasBuffer is the gas buffer
+	// added to the estimated transfer gas
+	transferGasBuffer = 10_000
+
+	// fundingMsgsPerTx is the maximum number
+	// of transfers packed into a single funding tx
+	fundingMsgsPerTx = 100
+
+	// fundingFanOut is the maximum number of funding txs a single account sends out.
+	// Above it, the funding is fanned out, and the first funded accounts fund the rest
+	fundingFanOut = 10
+)
+
+var (
+	errInsufficientFunds = errors.New("insufficient distributor funds")
+	errInclusionTimeout  = errors.New("txs were not committed in time")
+	errFundingFailed     = errors.New("sub-account funding failed")
+	errReclaimFailed     = errors.New("sub-account reclaim failed")
+)
 
 type Client interface {
 	GetAccount(ctx context.Context, address string) (*gnoland.GnoAccount, error)
 	BroadcastTransaction(ctx context.Context, tx *std.Tx) error
+	BroadcastTransactionSync(ctx context.Context, tx *std.Tx) error
 	EstimateGas(ctx context.Context, tx *std.Tx) (int64, error)
 	FetchGasPrice(ctx context.Context) (std.GasPrice, error)
 }
@@ -29,6 +55,9 @@ type Client interface {
 type Distributor struct {
 	cli Client
 	ctx context.Context
+
+	pollInterval     time.Duration
+	inclusionTimeout time.Duration
 }
 
 // NewDistributor creates a new instance of the distributor
@@ -37,18 +66,45 @@ func NewDistributor(
 	cli Client,
 ) *Distributor {
 	return &Distributor{
-		cli: cli,
-		ctx: ctx,
+		cli:              cli,
+		ctx:              ctx,
+		pollInterval:     time.Second,
+		inclusionTimeout: time.Minute * 2,
 	}
 }
 
+// fundingConfig is the configuration of the funding txs
+type fundingConfig struct {
+	gasPrice    std.GasPrice
+	chainID     string
+	transferGas int64 // the (estimated) gas of a single transfer
+	msgsPerTx   int   // the maximum number of transfers in a single funding tx
+}
+
+// transfer is a single funding transfer
+type transfer struct {
+	amount  std.Coin
+	address crypto.Address
+	key     crypto.PrivKey // the key of the funded account
+	account std.Account    // the funded account, as it is before the funding
+}
+
+// sweep is a single reclaim transfer, pending commit
+type sweep struct {
+	amount   std.Coin
+	address  crypto.Address
+	sequence uint64 // the sweeping account sequence, once the sweep is committed
+	fee      int64  // the sweep fee, the most the account can be left with
+}
+
 // Distribute distributes the funds from the base account
 // (account 0 in the mnemonic) to other subaccounts
 func (d *Distributor) Distribute(
 	distributor crypto.PrivKey,
-	accounts []crypto.Address,
+	accounts []crypto.PrivKey,
 	chainID string,
 	gasPrice std.GasPrice,
+	maxGas int64,
 	calculatedRuntimeCost std.Coin,
 ) ([]std.Account, error) {
 	fmt.Printf("\n💸 Starting Fund Distribution 💸\n\n")
@@ -60,34 +116,114 @@ func (d *Distributor) Distribute(
 	)
 
 	// Fund the accounts
-	return d.fundAccounts(distributor, accounts, calculatedRuntimeCost, chainID, gasPrice)
+	return d.fundAccounts(distributor, accounts, calculatedRuntimeCost, chainID, gasPrice, maxGas)
 }
 
-// fundAccounts attempts to fund accounts that have missing funds,
-// and returns the accounts that can participate in the stress test
-func (d *Distributor) fundAccounts(
-	distributorKey crypto.PrivKey,
-	accounts []crypto.Address,
-	singleRunCost std.Coin,
+// Plan is the funding plan of the sub-accounts,
+// calculated without sending out any funds
+type Plan struct {
+	Accounts []std.Account // the sub-accounts, as they are before the funding
+
+	Ready      int   // the number of sub-accounts that are already funded
+	Funded     int   // the number of sub-accounts the distributor can fund
+	Unfundable int   // the number of sub-accounts the distributor can't fund
+	Required   int64 // the funds needed to fund all short sub-accounts (transfers and fees)
+	Balance    int64 // the distributor balance
+
+	FundingTxs int // the number of funding txs
+	Funders    int // the number of funder accounts, if the funding is fanned out
+}
+
+// Plan calculates the funding plan of the sub-accounts, without
+// sending out any funds. The distributor is account 0 in the mnemonic
+func (d *Distributor) Plan(
+	distributor crypto.PrivKey,
+	accounts []crypto.PrivKey,
 	chainID string,
 	gasPrice std.GasPrice,
-) ([]std.Account, error) {
-	type shortAccount struct {
-		missingFunds std.Coin
-		address      crypto.Address
+	maxGas int64,
+	calculatedRuntimeCost std.Coin,
+) (*Plan, error) {
+	fmt.Printf("\n💸 Planning Fund Distribution 💸\n\n")
+
+	fmt.Printf(
+		"Calculated sub-account cost as %d %s\n",
+		calculatedRuntimeCost.Amount,
+		calculatedRuntimeCost.Denom,
+	)
+
+	funding, err := d.planFunding(distributor, accounts, calculatedRuntimeCost, chainID, gasPrice, maxGas)
+	if err != nil {
+		return nil, err
 	}
 
-	var (
+	plan := &Plan{
+		Accounts:   funding.ready,
+		Ready:      len(funding.ready),
+		Funded:     len(funding.transfers),
+		Unfundable: funding.unfundable,
+		Required:   funding.required,
+		Balance:    funding.balance,
+		FundingTxs: funding.numTxs,
+		Funders:    funding.numFunders,
+	}
+
+	for _, short := range funding.short {
+		account := short.account
+
+		// Accounts that don't exist on the node yet
+		// are only known by their address
+		if account.GetAddress().IsZero() {
+			if err := account.SetAddress(short.address); err != nil {
+				return nil, fmt.Errorf("unable to set account address, %w", err)
+			}
+		}
+
+		plan.Accounts = append(plan.Accounts, account)
+	}
+
+	return plan, nil
+}
+
+// fundingPlan is the plan of the sub-account funding
+type fundingPlan struct {
+	ready     []std.Account // accounts that are already funded
+	short     []transfer    // transfers to all accounts that need funding
+	transfers []transfer    // transfers to the accounts the distributor can fund
+
+	distributor std.Account
+	balance     int64 // the distributor balance
+	required    int64 // the funds needed to fund all short accounts
+	unfundable  int   // the number of short accounts the distributor can't fund
+
+	cfg        fundingConfig
+	numTxs     int // the number of funding txs
+	numFunders int // the number of funder accounts, if the funding is fanned out
+}
+
+// planFunding checks which accounts have missing funds, and figures
+// out how many of them can be funded, and how the funding txs are sent out
+func (d *Distributor) planFunding(
+	distributorKey crypto.PrivKey,
+	accounts []crypto.PrivKey,
+	singleRunCost std.Coin,
+	chainID string,
+	gasPrice std.GasPrice,
+	maxGas int64,
+) (*fundingPlan, error) {
+	plan := &fundingPlan{
 		// Accounts that are ready (funded) for the run
-		readyAccounts = make([]std.Account, 0, len(accounts))
+		ready: make([]std.Account, 0, len(accounts)),
 
 		// Accounts that need funding
-		shortAccounts = make([]shortAccount, 0, len(accounts))
-	)
+		short: make([]transfer, 0, len(accounts)),
+	}
 
 	// Check if there are any accounts that need to be funded
 	// before the stress test starts
-	for _, account := range accounts {
+	for _, key := range accounts {
+		account := key.PubKey().Address()
+
 		// Fetch the account balance
 		subAccount, err := d.cli.GetAccount(d.ctx, account.String())
 		if err != nil {
@@ -97,9 +233,11 @@ func (d *Distributor) fundAccounts(
 		// Check if it has enough funds for the run
 		if subAccount.Coins.AmountOf(common.Denomination) < singleRunCost.Amount {
 			// Mark the account as needing a top-up
-			shortAccounts = append(shortAccounts, shortAccount{
+			plan.short = append(plan.short, transfer{
 				address: account,
-				missingFunds: std.Coin{
+				key:     key,
+				account: subAccount,
+				amount: std.Coin{
 					Denom:  common.Denomination,
 					Amount: singleRunCost.Amount - subAccount.Coins.AmountOf(common.Denomination),
 				},
@@ -109,21 +247,18 @@ func (d *Distributor) fundAccounts(
 		}
 
 		// The account is cleared for the stress test
-		readyAccounts = append(readyAccounts, subAccount)
+		plan.ready = append(plan.ready, subAccount)
 	}
 
 	// Check if funding is even necessary
-	if len(shortAccounts) == 0 {
-		// All accounts are already funded
-		fmt.Printf("✅ All %d accounts are already funded\n", len(readyAccounts))
-
-		return readyAccounts, nil
+	if len(plan.short) == 0 {
+		return plan, nil
 	}
 
 	// Sort the short accounts so the ones with
 	// the lowest missing funds are funded first
-	sort.Slice(shortAccounts, func(i, j int) bool {
-		return shortAccounts[i].missingFunds.IsLT(shortAccounts[j].missingFunds)
+	sort.Slice(plan.short, func(i, j int) bool {
+		return plan.short[i].amount.IsLT(plan.short[j].amount)
 	})
 
 	// Figure out how many accounts can actually be funded
@@ -132,94 +267,524 @@ func (d *Distributor) fundAccounts(
 		return nil, fmt.Errorf("unable to fetch distributor account, %w", err)
 	}
 
+	// Estimate the gas of a single funding transfer
+	transferGas, err := d.estimateTransferGas(
+		distributorKey,
+		distributor,
+		plan.short[0].address,
+		chainID,
+		gasPrice,
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	var (
-		distributorBalance = distributor.Coins
+		distributorBalance = distributor.Coins.AmountOf(common.Denomination)
+		transferFee        = common.CalculateFeeInRatio(transferGas, gasPrice).GasFee.Amount
 		fundableIndex      = 0
-		defaultFee         = common.CalculateFeeInRatio(100_000, gasPrice)
 	)
 
-	for _, account := range shortAccounts {
+	plan.distributor = distributor
+	plan.balance = distributorBalance
+
+	displayFundingEstimate(plan.short, transferFee, distributorBalance)
+
+	for index, account := range plan.short {
 		// The transfer cost is the single run cost (missing balance) + approximate transfer cost
-		transferCost := std.NewCoins(defaultFee.GasFee.Add(account.missingFunds))
+		transferCost := account.amount.Amount + transferFee
+
+		plan.required += transferCost
+
+		// Accounts are funded in order, until the
+		// distributor runs out of funds to cover the run cost
+		if fundableIndex == index && distributorBalance >= transferCost {
+			fundableIndex++
 
-		if distributorBalance.IsAllLT(transferCost) {
-			// Distributor does not have any more funds
-			// to cover the run cost
-			break
+			distributorBalance -= transferCost
 		}
+	}
+
+	plan.transfers = plan.short[:fundableIndex]
+	plan.unfundable = len(plan.short) - fundableIndex
 
-		fundableIndex++
+	plan.cfg = fundingConfig{
+		gasPrice:    gasPrice,
+		chainID:     chainID,
+		transferGas: transferGas,
+		msgsPerTx:   fundingMsgsPerTx,
+	}
+
+	// Figure out how many transfers fit into a single funding tx
+	if maxGas > 0 {
+		plan.cfg.msgsPerTx = max(min(plan.cfg.msgsPerTx, int(maxGas/transferGas)), 1)
+	}
 
-		distributorBalance.Sub(transferCost)
+	// Fan out the funding if the distributor alone
+	// would need to send out too many funding txs
+	plan.numTxs = (len(plan.transfers) + plan.cfg.msgsPerTx - 1) / plan.cfg.msgsPerTx
+
+	if plan.numTxs > fundingFanOut {
+		plan.numFunders = (plan.numTxs + fundingFanOut - 1) / fundingFanOut
+	}
+
+	return plan, nil
+}
+
+// fundAccounts funds the accounts that have missing funds, and returns the accounts
+// ready for the stress test. Either all short accounts are funded, or none are.
+// Transfers are packed into funding txs, which are broadcast without waiting
+// for them to be committed. Large fundings are fanned out, so
+// the first funded accounts fund the rest of the accounts in parallel
+func (d *Distributor) fundAccounts(
+	distributorKey crypto.PrivKey,
+	accounts []crypto.PrivKey,
+	singleRunCost std.Coin,
+	chainID string,
+	gasPrice std.GasPrice,
+	maxGas int64,
+) ([]std.Account, error) {
+	plan, err := d.planFunding(distributorKey, accounts, singleRunCost, chainID, gasPrice, maxGas)
+	if err != nil {
+		return nil, err
 	}
 
-	if fundableIndex == 0 {
-		// The distributor does not have funds to fund
-		// any account for the stress test
+	readyAccounts := plan.ready
+
+	// Check if funding is even necessary
+	if len(plan.short) == 0 {
+		// All accounts are already funded
+		fmt.Printf("✅ All %d accounts are already funded\n", len(readyAccounts))
+
+		return readyAccounts, nil
+	}
+
+	if plan.unfundable > 0 {
+		// Each account is only funded for its own share of the run,
+		// so the run can't go on without the accounts the distributor
+		// can't fund. No funds are sent out in that case
 		fmt.Printf(
-			"❌ Distributor cannot fund any account, balance is %d %s\n",
-			distributorBalance.AmountOf(common.Denomination),
+			"❌ Distributor can fund %d out of %d accounts, balance is %d %s (%d %s required)\n",
+			len(plan.transfers),
+			len(plan.short),
+			plan.balance,
+			common.Denomination,
+			plan.required,
 			common.Denomination,
 		)
 
 		return nil, errInsufficientFunds
 	}
 
+	var (
+		shortAccounts = plan.transfers
+		distributor   = plan.distributor
+		cfg           = plan.cfg
+		numFunders    = plan.numFunders
+	)
+
+	fmt.Printf("Funding %d accounts...\n", len(shortAccounts))
+
+	if numFunders == 0 {
+		if err := d.sendTransfers(distributorKey, distributor, shortAccounts, cfg); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := d.fanOutTransfers(distributorKey, distributor, shortAccounts, numFunders, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	// Since accounts can be uninitialized on the node, after the
+	// transfer they will have acquired a storage slot, and need
+	// to be re-fetched for their data (Sequence + Account Number)
+	bar := progressbar.Default(int64(len(shortAccounts)), "funded accounts checked")
+
+	for _, account := range shortAccounts {
+		nodeAccount, err := d.cli.GetAccount(d.ctx, account.address.String())
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch account, %w", err)
+		}
+
+		// Make sure the funding went through
+		if nodeAccount.Coins.AmountOf(common.Denomination) < singleRunCost.Amount {
+			return nil, fmt.Errorf("%w, account %s is not funded", errFundingFailed, account.address)
+		}
+
+		// Mark the account as funded
+		readyAccounts = append(readyAccounts, nodeAccount)
+
+		_ = bar.Add(1) //nolint:errcheck // No need to check
+	}
+
+	fmt.Printf("✅ Successfully funded %d accounts\n", len(shortAccounts))
+
+	return readyAccounts, nil
+}
+
+// fanOutTransfers funds the given number of funder accounts first, with the funds for the accounts
+// they are responsible for, after which the funders fund the rest of the accounts in parallel
+func (d *Distributor) fanOutTransfers(
+	distributorKey crypto.PrivKey,
+	distributor std.Account,
+	transfers []transfer,
+	numFunders int,
+	cfg fundingConfig,
+) error {
+	var (
+		funders   = transfers[:numFunders]
+		rest      = transfers[numFunders:]
+		groupSize = (len(rest) + numFunders - 1) / numFunders
+
+		groups        = make([][]transfer, numFunders)
+		funderFunding = make([]transfer, numFunders)
+
+		transferFee = common.CalculateFeeInRatio(cfg.transferGas, cfg.gasPrice).GasFee.Amount
+	)
+
+	// Each funder receives its own missing funds, as well as the
+	// funds (and fees) for the transfers to the accounts in its group
+	for index, funder := range funders {
+		groups[index] = rest[min(index*groupSize, len(rest)):min((index+1)*groupSize, len(rest))]
+
+		amount := funder.amount.Amount
+		for _, account := range groups[index] {
+			amount += account.amount.Amount + transferFee
+		}
+
+		funderFunding[index] = transfer{
+			address: funder.address,
+			amount: std.Coin{
+				Denom:  common.Denomination,
+				Amount: amount,
+			},
+		}
+	}
+
+	fmt.Printf("Funding %d funder accounts...\n", numFunders)
+
+	if err := d.sendTransfers(distributorKey, distributor, funderFunding, cfg); err != nil {
+		return fmt.Errorf("unable to fund funder accounts, %w", err)
+	}
+
+	fmt.Printf("Funding the remaining %d accounts over %d funders...\n", len(rest), numFunders)
+
+	var (
+		wg   sync.WaitGroup
+		errs = make([]error, numFunders)
+	)
+
+	for index, funder := range funders {
+		if len(groups[index]) == 0 {
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(index int, funder transfer) {
+			defer wg.Done()
+
+			// The funder was just funded, so it needs
+			// to be fetched for its data (Sequence + Account Number)
+			funderAccount, err := d.cli.GetAccount(d.ctx, funder.address.String())
+			if err != nil {
+				errs[index] = fmt.Errorf("unable to fetch funder account, %w", err)
+
+				return
+			}
+
+			errs[index] = d.sendTransfers(funder.key, funderAccount, groups[index], cfg)
+		}(index, funder)
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// sendTransfers packs the transfers into funding txs, which are signed by the sender,
+// and broadcast one after another without waiting for them to be committed.
+// Once all funding txs are broadcast, it waits for them to be committed
+func (d *Distributor) sendTransfers(
+	senderKey crypto.PrivKey,
+	sender std.Account,
+	transfers []transfer,
+	cfg fundingConfig,
+) error {
 	// Locally keep track of the nonce, so
 	// there is no need to re-fetch the account again
 	// before signing a future tx
-	nonce := distributor.Sequence
+	nonce := sender.GetSequence()
 
-	fmt.Printf("Funding %d accounts...\n", len(shortAccounts))
-	bar := progressbar.Default(int64(len(shortAccounts)), "funding short accounts")
+	for start := 0; start < len(transfers); start += cfg.msgsPerTx {
+		var (
+			chunk = transfers[start:min(start+cfg.msgsPerTx, len(transfers))]
+			msgs  = make([]std.Msg, 0, len(chunk))
+		)
+
+		for _, account := range chunk {
+			msgs = append(msgs, bank.MsgSend{
+				FromAddress: sender.GetAddress(),
+				ToAddress:   account.address,
+				Amount:      std.NewCoins(account.amount),
+			})
+		}
+
+		// Generate the transaction
+		tx := &std.Tx{
+			Msgs: msgs,
+			Fee:  common.CalculateFeeInRatio(int64(len(chunk))*cfg.transferGas, cfg.gasPrice),
+		}
+
+		signCfg := signer.SignCfg{
+			ChainID:       cfg.chainID,
+			AccountNumber: sender.GetAccountNumber(),
+			Sequence:      nonce,
+		}
+
+		// Sign the transaction
+		if err := signer.SignTx(tx, senderKey, signCfg); err != nil {
+			return fmt.Errorf("unable to sign transaction, %w", err)
+		}
+
+		// Update the local nonce
+		nonce++
+
+		// Broadcast the tx, without waiting for it to be committed
+		if err := d.cli.BroadcastTransactionSync(d.ctx, tx); err != nil {
+			return fmt.Errorf("unable to broadcast tx, %w", err)
+		}
+	}
+
+	return d.waitForSequence(sender.GetAddress(), nonce)
+}
+
+// estimateTransferGas estimates the gas of a single transfer
+// from the sender account, including the gas buffer
+func (d *Distributor) estimateTransferGas(
+	senderKey crypto.PrivKey,
+	sender std.Account,
+	recipient crypto.Address,
+	chainID string,
+	gasPrice std.GasPrice,
+) (int64, error) {
+	tx := &std.Tx{
+		Msgs: []std.Msg{
+			bank.MsgSend{
+				FromAddress: sender.GetAddress(),
+				ToAddress:   recipient,
+				Amount:      std.NewCoins(std.NewCoin(common.Denomination, 1)),
+			},
+		},
+		Fee: common.CalculateFeeInRatio(defaultTransferGas, gasPrice),
+	}
+
+	cfg := signer.SignCfg{
+		ChainID:       chainID,
+		AccountNumber: sender.GetAccountNumber(),
+		Sequence:      sender.GetSequence(),
+	}
+
+	// Sign the transaction
+	if err := signer.SignTx(tx, senderKey, cfg); err != nil {
+		return 0, fmt.Errorf("unable to sign transaction, %w", err)
+	}
+
+	gas, err := d.cli.EstimateGas(d.ctx, tx)
+	if err != nil {
+		return 0, fmt.Errorf("unable to estimate transfer gas, %w", err)
+	}
+
+	return gas + transferGasBuffer, nil
+}
+
+// displayFundingEstimate displays the pre-flight estimate
+// of the funds needed to fund the short accounts
+func displayFundingEstimate(transfers []transfer, transferFee, balance int64) {
+	var total int64
+
+	for _, account := range transfers {
+		total += account.amount.Amount
+	}
+
+	fees := int64(len(transfers)) * transferFee
+
+	fmt.Printf("Funding estimate:\n")
+	fmt.Printf("- accounts to fund: %d\n", len(transfers))
+	fmt.Printf("- transferred: %d %s\n", total, common.Denomination)
+	fmt.Printf("- funding fees: %d %s (%d %s per account)\n", fees, common.Denomination, transferFee, common.Denomination)
+	fmt.Printf(
+		"- total: %d %s (distributor balance is %d %s)\n",
+		total+fees, common.Denomination,
+		balance, common.Denomination,
+	)
+}
+
+// waitForSequence waits until the account sequence reaches the given value,
+// meaning all the account txs up until it have been committed
+func (d *Distributor) waitForSequence(address crypto.Address, sequence uint64) error {
+	timeout := time.After(d.inclusionTimeout)
+
+	for {
+		account, err := d.cli.GetAccount(d.ctx, address.String())
+		if err != nil {
+			return fmt.Errorf("unable to fetch account, %w", err)
+		}
+
+		if account.Sequence >= sequence {
+			return nil
+		}
+
+		select {
+		case <-d.ctx.Done():
+			return d.ctx.Err()
+		case <-timeout:
+			return fmt.Errorf("%w, account %s", errInclusionTimeout, address)
+		case <-time.After(d.pollInterval):
+		}
+	}
+}
+
+// Reclaim sends the remaining funds of the sub-accounts back to the
+// distributor (account 0 in the mnemonic), and returns the reclaimed amount.
+// The sweeps are broadcast without waiting for each one to be committed,
+// after which every sweep is verified, and the accounts that were not emptied are reported.
+// Accounts that can't cover the transfer fee are skipped
+func (d *Distributor) Reclaim(
+	distributor crypto.Address,
+	accounts []crypto.PrivKey,
+	chainID string,
+	gasPrice std.GasPrice,
+) (std.Coin, error) {
+	fmt.Printf("\n♻️ Starting Fund Reclamation ♻️\n\n")
+
+	var (
+		reclaimed = std.Coin{Denom: common.Denomination, Amount: 0}
+		sweeps    = make([]sweep, 0, len(accounts))
+
+		// The transfer gas can only be estimated if the
+		// account covers the fee assumed for the estimation
+		estimationFee = common.CalculateFeeInRatio(defaultTransferGas, gasPrice)
+
+		bar = progressbar.Default(int64(len(accounts)), "accounts swept")
+	)
+
+	for _, key := range accounts {
+		address := key.PubKey().Address()
+
+		// Fetch the account balance
+		account, err := d.cli.GetAccount(d.ctx, address.String())
+		if err != nil {
+			return reclaimed, fmt.Errorf("unable to fetch sub-account, %w", err)
+		}
+
+		balance := account.Coins.AmountOf(common.Denomination)
+		if balance <= estimationFee.GasFee.Amount {
+			_ = bar.Add(1) //nolint:errcheck // No need to check
+
+			continue
+		}
+
+		// Estimate the fee of the transfer
+		transferGas, err := d.estimateTransferGas(key, account, distributor, chainID, gasPrice)
+		if err != nil {
+			return reclaimed, err
+		}
+
+		fee := common.CalculateFeeInRatio(transferGas, gasPrice)
+
+		// Check if the balance covers the transfer fee
+		if balance <= fee.GasFee.Amount {
+			_ = bar.Add(1) //nolint:errcheck // No need to check
+
+			continue
+		}
+
+		amount := std.Coin{
+			Denom:  common.Denomination,
+			Amount: balance - fee.GasFee.Amount,
+		}
 
-	for _, account := range shortAccounts {
 		// Generate the transaction
 		tx := &std.Tx{
 			Msgs: []std.Msg{
 				bank.MsgSend{
-					FromAddress: distributor.GetAddress(),
-					ToAddress:   account.address,
-					Amount:      std.NewCoins(account.missingFunds),
+					FromAddress: address,
+					ToAddress:   distributor,
+					Amount:      std.NewCoins(amount),
 				},
 			},
-			Fee: defaultFee,
+			Fee: fee,
 		}
 
 		cfg := signer.SignCfg{
 			ChainID:       chainID,
-			AccountNumber: distributor.AccountNumber,
-			Sequence:      nonce,
+			AccountNumber: account.AccountNumber,
+			Sequence:      account.Sequence,
 		}
 
 		// Sign the transaction
-		if err := signer.SignTx(tx, distributorKey, cfg); err != nil {
-			return nil, fmt.Errorf("unable to sign transaction, %w", err)
+		if err := signer.SignTx(tx, key, cfg); err != nil {
+			return reclaimed, fmt.Errorf("unable to sign transaction, %w", err)
 		}
 
-		// Update the local nonce
-		nonce++
+		// Broadcast the tx, without waiting for it to be committed
+		if err := d.cli.BroadcastTransactionSync(d.ctx, tx); err != nil {
+			return reclaimed, fmt.Errorf("unable to broadcast tx, %w", err)
+		}
+
+		sweeps = append(sweeps, sweep{
+			address:  address,
+			sequence: account.Sequence + 1,
+			amount:   amount,
+			fee:      fee.GasFee.Amount,
+		})
+
+		_ = bar.Add(1) //nolint:errcheck // No need to check
+	}
 
-		// Broadcast the tx and wait for it to be committed
-		if err := d.cli.BroadcastTransaction(d.ctx, tx); err != nil {
-			return nil, fmt.Errorf("unable to broadcast tx with commit, %w", err)
+	// Make sure the sweeps went through
+	failed := make([]crypto.Address, 0)
+
+	bar = progressbar.Default(int64(len(sweeps)), "sweeps committed")
+
+	for _, sweep := range sweeps {
+		if err := d.waitForSequence(sweep.address, sweep.sequence); err != nil {
+			return reclaimed, fmt.Errorf("unable to verify sweep, %w", err)
 		}
 
-		// Since accounts can be uninitialized on the node, after the
-		// transfer they will have acquired a storage slot, and need
-		// to be re-fetched for their data (Sequence + Account Number)
-		nodeAccount, err := d.cli.GetAccount(d.ctx, account.address.String())
+		// The sequence also moves past failed sweeps,
+		// so make sure the funds actually left the account
+		account, err := d.cli.GetAccount(d.ctx, sweep.address.String())
 		if err != nil {
-			return nil, fmt.Errorf("unable to fetch account, %w", err)
+			return reclaimed, fmt.Errorf("unable to fetch sub-account, %w", err)
 		}
 
-		// Mark the account as funded
-		readyAccounts = append(readyAccounts, nodeAccount)
+		if account.Coins.AmountOf(common.Denomination) > sweep.fee {
+			failed = append(failed, sweep.address)
+		} else {
+			reclaimed = reclaimed.Add(sweep.amount)
+		}
 
 		_ = bar.Add(1) //nolint:errcheck // No need to check
 	}
 
-	fmt.Printf("✅ Successfully funded %d accounts\n", len(shortAccounts))
+	fmt.Printf(
+		"✅ Successfully reclaimed %d %s from %d accounts\n",
+		reclaimed.Amount,
+		reclaimed.Denom,
+		len(sweeps)-len(failed),
+	)
 
-	return readyAccounts, nil
+	if len(failed) > 0 {
+		fmt.Printf("⚠️ Unable to reclaim the funds of %d accounts:\n", len(failed))
+
+		for _, address := range failed {
+			fmt.Printf("- %s\n", address)
+		}
+
+		return reclaimed, fmt.Errorf("%w, %d accounts were not emptied", errReclaimFailed, len(failed))
+	}
+
+	return reclaimed, nil
 }
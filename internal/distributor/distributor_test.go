@@ -2,7 +2,9 @@ package distributor
 
 import (
 	"context"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/gnolang/gno/gno.land/pkg/gnoland"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
@@ -11,8 +13,69 @@ import (
 	"github.com/gnolang/supernova/internal/common"
 	testutils "github.com/gnolang/supernova/internal/testing"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+// testLedger is a simple in-memory ledger,
+// which applies the broadcast transfers right away
+type testLedger struct {
+	balances  map[string]int64
+	sequences map[string]uint64
+	txs       []*std.Tx
+
+	mux sync.Mutex
+}
+
+// newTestLedger creates a new test ledger, with the given initial balances
+func newTestLedger(balances map[string]int64) *testLedger {
+	return &testLedger{
+		balances:  balances,
+		sequences: make(map[string]uint64),
+	}
+}
+
+// getAccount returns the ledger account
+func (l *testLedger) getAccount(_ context.Context, address string) (*gnoland.GnoAccount, error) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	return &gnoland.GnoAccount{
+		BaseAccount: *std.NewBaseAccount(
+			crypto.MustAddressFromString(address),
+			std.NewCoins(std.Coin{
+				Denom:  common.Denomination,
+				Amount: l.balances[address],
+			}),
+			nil,
+			0,
+			l.sequences[address],
+		),
+	}, nil
+}
+
+// broadcast applies the transfers of the tx, and charges the fee
+func (l *testLedger) broadcast(_ context.Context, tx *std.Tx) error {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	l.txs = append(l.txs, tx)
+
+	sender := tx.Msgs[0].(bank.MsgSend).FromAddress.String()
+
+	l.balances[sender] -= tx.Fee.GasFee.Amount
+	l.sequences[sender]++
+
+	for _, msg := range tx.Msgs {
+		send := msg.(bank.MsgSend)
+		amount := send.Amount.AmountOf(common.Denomination)
+
+		l.balances[send.FromAddress.String()] -= amount
+		l.balances[send.ToAddress.String()] += amount
+	}
+
+	return nil
+}
+
 func TestDistributor_Distribute(t *testing.T) {
 	t.Parallel()
 
@@ -65,13 +128,7 @@ func TestDistributor_Distribute(t *testing.T) {
 			mockClient,
 		)
 
-		// Extract the addresses
-		addresses := make([]crypto.Address, 0, len(accounts[1:]))
-		for _, account := range accounts[1:] {
-			addresses = append(addresses, account.PubKey().Address())
-		}
-
-		readyAccounts, err := d.Distribute(accounts[0], addresses, "dummy", common.DefaultGasPrice, singleCost)
+		readyAccounts, err := d.Distribute(accounts[0], accounts[1:], "dummy", common.DefaultGasPrice, 0, singleCost)
 		if err != nil {
 			t.Fatalf("unable to distribute funds, %v", err)
 		}
@@ -122,69 +179,59 @@ func TestDistributor_Distribute(t *testing.T) {
 			mockClient,
 		)
 
-		// Extract the addresses
-		addresses := make([]crypto.Address, 0, len(accounts[1:]))
-		for _, account := range accounts[1:] {
-			addresses = append(addresses, account.PubKey().Address())
-		}
-
-		readyAccounts, err := d.Distribute(accounts[0], addresses, "dummy", common.DefaultGasPrice, singleCost)
+		readyAccounts, err := d.Distribute(accounts[0], accounts[1:], "dummy", common.DefaultGasPrice, 0, singleCost)
 
 		assert.Nil(t, readyAccounts)
 		assert.ErrorIs(t, err, errInsufficientFunds)
 	})
 
-	t.Run("fund all short accounts", func(t *testing.T) {
+	t.Run("partially fundable accounts", func(t *testing.T) {
 		t.Parallel()
 
-		emptyBalance := std.Coin{
-			Denom:  common.Denomination,
-			Amount: 0,
-		}
-
 		var (
-			accounts           = testutils.GenerateAccounts(t, 10)
-			capturedBroadcasts = make([]*std.Tx, 0)
+			accounts = testutils.GenerateAccounts(t, 10)
+			sendCost = common.CalculateFeeInRatio(100_000, common.DefaultGasPrice)
+
+			// The distributor can only fund a few of the accounts
+			ledger = newTestLedger(map[string]int64{
+				accounts[0].PubKey().Address().String(): 3 * sendCost.GasFee.Add(singleCost).Amount,
+			})
 
 			mockClient = &mockClient{
-				getAccountFn: func(ctx context.Context, address string) (*gnoland.GnoAccount, error) {
-					acc := getAccount(address, accounts)
-					if acc == nil {
-						t.Fatal("invalid account requested")
-					}
+				getAccountFn:               ledger.getAccount,
+				broadcastTransactionSyncFn: ledger.broadcast,
+			}
+		)
 
-					if acc.Equals(accounts[0]) {
-						sendCost := common.CalculateFeeInRatio(100_000, common.DefaultGasPrice)
+		d := NewDistributor(
+			context.Background(),
+			mockClient,
+		)
+		d.pollInterval = 0
 
-						return &gnoland.GnoAccount{
-							BaseAccount: *std.NewBaseAccount(
-								acc.PubKey().Address(),
-								std.NewCoins(std.Coin{
-									Denom:  common.Denomination,
-									Amount: int64(numTx) * sendCost.GasFee.Add(singleCost).Amount,
-								}),
-								nil,
-								0,
-								0,
-							),
-						}, nil
-					}
+		readyAccounts, err := d.Distribute(accounts[0], accounts[1:], "dummy", common.DefaultGasPrice, 0, singleCost)
 
-					return &gnoland.GnoAccount{
-						BaseAccount: *std.NewBaseAccount(
-							acc.PubKey().Address(),
-							std.NewCoins(emptyBalance),
-							nil,
-							0,
-							0,
-						),
-					}, nil
-				},
-				broadcastTransactionFn: func(ctx context.Context, tx *std.Tx) error {
-					capturedBroadcasts = append(capturedBroadcasts, tx)
+		assert.Nil(t, readyAccounts)
+		assert.ErrorIs(t, err, errInsufficientFunds)
 
-					return nil
-				},
+		// Make sure no funds were sent out
+		assert.Empty(t, ledger.txs)
+	})
+
+	t.Run("fund all short accounts", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			accounts = testutils.GenerateAccounts(t, 10)
+			sendCost = common.CalculateFeeInRatio(100_000, common.DefaultGasPrice)
+
+			ledger = newTestLedger(map[string]int64{
+				accounts[0].PubKey().Address().String(): int64(numTx) * sendCost.GasFee.Add(singleCost).Amount,
+			})
+
+			mockClient = &mockClient{
+				getAccountFn:               ledger.getAccount,
+				broadcastTransactionSyncFn: ledger.broadcast,
 			}
 		)
 
@@ -192,14 +239,9 @@ func TestDistributor_Distribute(t *testing.T) {
 			context.Background(),
 			mockClient,
 		)
+		d.pollInterval = 0
 
-		// Extract the addresses
-		addresses := make([]crypto.Address, 0, len(accounts[1:]))
-		for _, account := range accounts[1:] {
-			addresses = append(addresses, account.PubKey().Address())
-		}
-
-		readyAccounts, err := d.Distribute(accounts[0], addresses, "dummy", common.DefaultGasPrice, singleCost)
+		readyAccounts, err := d.Distribute(accounts[0], accounts[1:], "dummy", common.DefaultGasPrice, 0, singleCost)
 		if err != nil {
 			t.Fatalf("unable to distribute funds, %v", err)
 		}
@@ -211,19 +253,343 @@ func TestDistributor_Distribute(t *testing.T) {
 		// Make sure the accounts match
 		for index, account := range accounts[1:] {
 			assert.Equal(t, account.PubKey().Address(), readyAccounts[index].GetAddress())
+			assert.Equal(t, singleCost.Amount, readyAccounts[index].GetCoins().AmountOf(common.Denomination))
 		}
 
-		// Check the broadcast transactions
-		assert.Len(t, capturedBroadcasts, len(accounts)-1)
+		// Check the broadcast transactions.
+		// All transfers fit into a single funding tx
+		require.Len(t, ledger.txs, 1)
+		assert.Len(t, ledger.txs[0].Msgs, len(accounts)-1)
 
 		sendType := bank.MsgSend{}.Type()
 
-		for _, tx := range capturedBroadcasts {
-			if len(tx.Msgs) != 1 {
-				t.Fatal("invalid number of messages")
+		for _, msg := range ledger.txs[0].Msgs {
+			assert.Equal(t, sendType, msg.Type())
+		}
+	})
+
+	t.Run("fan out the funding", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			accounts = testutils.GenerateAccounts(t, 31)
+			sendCost = common.CalculateFeeInRatio(100_000, common.DefaultGasPrice)
+
+			// Each funding tx fits only 2 transfers, so
+			// the distributor would need to send out 15 txs
+			transferGas = int64(50_000)
+			maxGas      = 2 * (transferGas + transferGasBuffer)
+			distributor = accounts[0].PubKey().Address()
+
+			ledger = newTestLedger(map[string]int64{
+				distributor.String(): int64(len(accounts)) * sendCost.GasFee.Add(singleCost).Amount,
+			})
+
+			mockClient = &mockClient{
+				getAccountFn:               ledger.getAccount,
+				broadcastTransactionSyncFn: ledger.broadcast,
+				estimateGasFn: func(_ context.Context, _ *std.Tx) (int64, error) {
+					return transferGas, nil
+				},
 			}
+		)
+
+		d := NewDistributor(
+			context.Background(),
+			mockClient,
+		)
+		d.pollInterval = 0
 
-			assert.Equal(t, sendType, tx.Msgs[0].Type())
+		readyAccounts, err := d.Distribute(accounts[0], accounts[1:], "dummy", common.DefaultGasPrice, maxGas, singleCost)
+		require.NoError(t, err)
+
+		// Make sure all accounts are funded
+		require.Len(t, readyAccounts, len(accounts)-1)
+
+		for _, account := range readyAccounts {
+			assert.GreaterOrEqual(t, account.GetCoins().AmountOf(common.Denomination), singleCost.Amount)
+		}
+
+		// Make sure the distributor only funded the funders,
+		// and the funders funded the rest of the accounts
+		var (
+			distributorTxs = 0
+			funders        = make(map[string]struct{})
+		)
+
+		for _, tx := range ledger.txs {
+			assert.LessOrEqual(t, len(tx.Msgs), 2)
+
+			sender := tx.Msgs[0].(bank.MsgSend).FromAddress
+			if sender == distributor {
+				distributorTxs++
+
+				continue
+			}
+
+			funders[sender.String()] = struct{}{}
+		}
+
+		assert.Equal(t, 1, distributorTxs)
+		assert.Len(t, funders, 2)
+
+		// Make sure the funding fees are based on the estimated transfer gas
+		for _, tx := range ledger.txs {
+			assert.Equal(t, int64(len(tx.Msgs))*(transferGas+transferGasBuffer), tx.Fee.GasWanted)
 		}
 	})
 }
+
+func TestDistributor_Plan(t *testing.T) {
+	t.Parallel()
+
+	var (
+		accounts    = testutils.GenerateAccounts(t, 10)
+		distributor = accounts[0].PubKey().Address()
+		singleCost  = std.Coin{
+			Denom:  common.Denomination,
+			Amount: 100_000,
+		}
+
+		// The estimated transfer gas is only the buffer
+		transferFee = common.CalculateFeeInRatio(transferGasBuffer, common.DefaultGasPrice).GasFee.Amount
+
+		// The first 3 sub-accounts are funded, and the rest
+		// don't exist yet. The distributor can only fund 4 of them
+		funded  = accounts[1:4]
+		balance = 4*(singleCost.Amount+transferFee) + transferFee
+
+		broadcasts = 0
+
+		mockClient = &mockClient{
+			getAccountFn: func(_ context.Context, address string) (*gnoland.GnoAccount, error) {
+				if address == distributor.String() {
+					return &gnoland.GnoAccount{
+						BaseAccount: *std.NewBaseAccount(
+							distributor,
+							std.NewCoins(std.NewCoin(common.Denomination, balance)),
+							nil,
+							0,
+							0,
+						),
+					}, nil
+				}
+
+				for _, account := range funded {
+					if address == account.PubKey().Address().String() {
+						return &gnoland.GnoAccount{
+							BaseAccount: *std.NewBaseAccount(
+								account.PubKey().Address(),
+								std.NewCoins(singleCost),
+								nil,
+								0,
+								0,
+							),
+						}, nil
+					}
+				}
+
+				// The account does not exist on the node
+				return &gnoland.GnoAccount{}, nil
+			},
+			broadcastTransactionFn: func(_ context.Context, _ *std.Tx) error {
+				broadcasts++
+
+				return nil
+			},
+			broadcastTransactionSyncFn: func(_ context.Context, _ *std.Tx) error {
+				broadcasts++
+
+				return nil
+			},
+		}
+	)
+
+	d := NewDistributor(context.Background(), mockClient)
+
+	plan, err := d.Plan(accounts[0], accounts[1:], "dummy", common.DefaultGasPrice, 0, singleCost)
+	require.NoError(t, err)
+
+	// Make sure nothing was sent out
+	assert.Zero(t, broadcasts)
+
+	assert.Equal(t, 3, plan.Ready)
+	assert.Equal(t, 4, plan.Funded)
+	assert.Equal(t, 2, plan.Unfundable)
+	assert.Equal(t, 6*(singleCost.Amount+transferFee), plan.Required)
+	assert.Equal(t, 1, plan.FundingTxs)
+	assert.Zero(t, plan.Funders)
+
+	// Make sure all sub-accounts are in the plan,
+	// including the ones that don't exist yet
+	require.Len(t, plan.Accounts, len(accounts)-1)
+
+	addresses := make(map[crypto.Address]struct{}, len(plan.Accounts))
+	for _, account := range plan.Accounts {
+		addresses[account.GetAddress()] = struct{}{}
+	}
+
+	for _, account := range accounts[1:] {
+		assert.Contains(t, addresses, account.PubKey().Address())
+	}
+}
+
+func TestDistributor_Reclaim(t *testing.T) {
+	t.Parallel()
+
+	var (
+		accounts    = testutils.GenerateAccounts(t, 10)
+		distributor = accounts[0].PubKey().Address()
+		transferGas = int64(50_000)
+		fee         = common.CalculateFeeInRatio(transferGas+transferGasBuffer, common.DefaultGasPrice)
+		balance     = fee.GasFee.Amount * 10
+
+		// The last sub-account can't cover the transfer fee
+		emptyAccount = accounts[len(accounts)-1].PubKey().Address()
+
+		ledger = newTestLedger(map[string]int64{
+			emptyAccount.String(): fee.GasFee.Amount,
+		})
+
+		mockClient = &mockClient{
+			getAccountFn:               ledger.getAccount,
+			broadcastTransactionSyncFn: ledger.broadcast,
+			estimateGasFn: func(_ context.Context, _ *std.Tx) (int64, error) {
+				return transferGas, nil
+			},
+		}
+	)
+
+	for _, account := range accounts[1 : len(accounts)-1] {
+		ledger.balances[account.PubKey().Address().String()] = balance
+	}
+
+	d := NewDistributor(
+		context.Background(),
+		mockClient,
+	)
+	d.pollInterval = 0
+
+	reclaimed, err := d.Reclaim(distributor, accounts[1:], "dummy", common.DefaultGasPrice)
+	require.NoError(t, err)
+
+	// Make sure the sub-account balances (minus fees) are reclaimed
+	swept := len(accounts) - 2
+	sendAmount := balance - fee.GasFee.Amount
+
+	assert.Equal(t, common.Denomination, reclaimed.Denom)
+	assert.Equal(t, int64(swept)*sendAmount, reclaimed.Amount)
+	assert.Equal(t, reclaimed.Amount, ledger.balances[distributor.String()])
+
+	// Check the broadcast transactions
+	require.Len(t, ledger.txs, swept)
+
+	for index, tx := range ledger.txs {
+		require.Len(t, tx.Msgs, 1)
+
+		msg, ok := tx.Msgs[0].(bank.MsgSend)
+		require.True(t, ok)
+
+		assert.Equal(t, accounts[index+1].PubKey().Address(), msg.FromAddress)
+		assert.Equal(t, distributor, msg.ToAddress)
+		assert.Equal(t, sendAmount, msg.Amount.AmountOf(common.Denomination))
+		assert.Equal(t, fee, tx.Fee)
+		assert.Len(t, tx.Signatures, 1)
+
+		// Make sure the swept accounts are emptied
+		assert.Zero(t, ledger.balances[msg.FromAddress.String()])
+	}
+}
+
+func TestDistributor_ReclaimFailed(t *testing.T) {
+	t.Parallel()
+
+	var (
+		accounts    = testutils.GenerateAccounts(t, 4)
+		distributor = accounts[0].PubKey().Address()
+		transferGas = int64(50_000)
+		fee         = common.CalculateFeeInRatio(transferGas+transferGasBuffer, common.DefaultGasPrice)
+		balance     = fee.GasFee.Amount * 10
+
+		// The sweep of the last sub-account is committed, but fails
+		failedAccount = accounts[len(accounts)-1].PubKey().Address()
+
+		ledger = newTestLedger(make(map[string]int64))
+
+		mockClient = &mockClient{
+			getAccountFn: ledger.getAccount,
+			broadcastTransactionSyncFn: func(ctx context.Context, tx *std.Tx) error {
+				if tx.Msgs[0].(bank.MsgSend).FromAddress != failedAccount {
+					return ledger.broadcast(ctx, tx)
+				}
+
+				// The failed tx only charges the fee
+				ledger.mux.Lock()
+				defer ledger.mux.Unlock()
+
+				ledger.balances[failedAccount.String()] -= tx.Fee.GasFee.Amount
+				ledger.sequences[failedAccount.String()]++
+
+				return nil
+			},
+			estimateGasFn: func(_ context.Context, _ *std.Tx) (int64, error) {
+				return transferGas, nil
+			},
+		}
+	)
+
+	for _, account := range accounts[1:] {
+		ledger.balances[account.PubKey().Address().String()] = balance
+	}
+
+	d := NewDistributor(
+		context.Background(),
+		mockClient,
+	)
+	d.pollInterval = 0
+
+	reclaimed, err := d.Reclaim(distributor, accounts[1:], "dummy", common.DefaultGasPrice)
+	assert.ErrorIs(t, err, errReclaimFailed)
+
+	// Make sure only the emptied accounts count as reclaimed
+	swept := int64(len(accounts) - 2)
+
+	assert.Equal(t, swept*(balance-fee.GasFee.Amount), reclaimed.Amount)
+	assert.Equal(t, reclaimed.Amount, ledger.balances[distributor.String()])
+	assert.Equal(t, balance-fee.GasFee.Amount, ledger.balances[failedAccount.String()])
+}
+
+func TestDistributor_ReclaimNotCommitted(t *testing.T) {
+	t.Parallel()
+
+	var (
+		accounts    = testutils.GenerateAccounts(t, 2)
+		distributor = accounts[0].PubKey().Address()
+		transferGas = int64(50_000)
+		fee         = common.CalculateFeeInRatio(transferGas+transferGasBuffer, common.DefaultGasPrice)
+
+		ledger = newTestLedger(map[string]int64{
+			accounts[1].PubKey().Address().String(): fee.GasFee.Amount * 10,
+		})
+
+		// The sweeps are accepted, but never committed
+		mockClient = &mockClient{
+			getAccountFn: ledger.getAccount,
+			estimateGasFn: func(_ context.Context, _ *std.Tx) (int64, error) {
+				return transferGas, nil
+			},
+		}
+	)
+
+	d := NewDistributor(
+		context.Background(),
+		mockClient,
+	)
+	d.pollInterval = 0
+	d.inclusionTimeout = 10 * time.Millisecond
+
+	reclaimed, err := d.Reclaim(distributor, accounts[1:], "dummy", common.DefaultGasPrice)
+
+	assert.ErrorIs(t, err, errInclusionTimeout)
+	assert.Zero(t, reclaimed.Amount)
+}
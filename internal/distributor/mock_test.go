@@ -8,17 +8,19 @@ import (
 )
 
 type (
-	broadcastTransactionDelegate func(context.Context, *std.Tx) error
-	getAccountDelegate           func(context.Context, string) (*gnoland.GnoAccount, error)
-	estimateGasDelegate          func(context.Context, *std.Tx) (int64, error)
-	fetchGasPriceDelegate        func(context.Context) (std.GasPrice, error)
+	broadcastTransactionDelegate     func(context.Context, *std.Tx) error
+	broadcastTransactionSyncDelegate func(context.Context, *std.Tx) error
+	getAccountDelegate               func(context.Context, string) (*gnoland.GnoAccount, error)
+	estimateGasDelegate              func(context.Context, *std.Tx) (int64, error)
+	fetchGasPriceDelegate            func(context.Context) (std.GasPrice, error)
 )
 
 type mockClient struct {
-	broadcastTransactionFn broadcastTransactionDelegate
-	getAccountFn           getAccountDelegate
-	estimateGasFn          estimateGasDelegate
-	fetchGasPriceFn        fetchGasPriceDelegate
+	broadcastTransactionFn     broadcastTransactionDelegate
+	broadcastTransactionSyncFn broadcastTransactionSyncDelegate
+	getAccountFn               getAccountDelegate
+	estimateGasFn              estimateGasDelegate
+	fetchGasPriceFn            fetchGasPriceDelegate
 }
 
 func (m *mockClient) BroadcastTransaction(ctx context.Context, tx *std.Tx) error {
@@ -29,6 +31,14 @@ func (m *mockClient) BroadcastTransaction(ctx context.Context, tx *std.Tx) error
 	return nil
 }
 
+func (m *mockClient) BroadcastTransactionSync(ctx context.Context, tx *std.Tx) error {
+	if m.broadcastTransactionSyncFn != nil {
+		return m.broadcastTransactionSyncFn(ctx, tx)
+	}
+
+	return nil
+}
+
 func (m *mockClient) GetAccount(ctx context.Context, address string) (*gnoland.GnoAccount, error) {
 	if m.getAccountFn != nil {
 		return m.getAccountFn(ctx, address)
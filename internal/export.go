@@ -0,0 +1,230 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/supernova/internal/batcher"
+	"github.com/gnolang/supernova/internal/collector"
+	"github.com/gnolang/supernova/internal/common"
+	"github.com/gnolang/supernova/internal/txfile"
+	"github.com/schollz/progressbar/v3"
+)
+
+var (
+	errInvalidExport   = errors.New("only single-stage transaction runs can be exported")
+	errAccountMismatch = errors.New("exported account does not match the node account")
+	errNoReplayTxs     = errors.New("no transactions to replay")
+)
+
+// Export prepares the run (predeploying any pending transactions, and funding the sub-accounts),
+// after which the run transactions are constructed, signed and written to the file at the given path,
+// instead of being sent out. The exported transactions can be sent out later using Replay
+func (p *Pipeline) Export(ctx context.Context, path string) error {
+	stages := p.cfg.stages()
+	if len(stages) != 1 || stages[0].isQueryOnly() {
+		return errInvalidExport
+	}
+
+	stage, err := p.withHistory(ctx, stages[0])
+	if err != nil {
+		return err
+	}
+
+	// Initialize the accounts for the run
+	accounts := p.initializeAccounts()
+
+	gasPrice, err := p.cli.FetchGasPrice(ctx)
+	if err != nil {
+		return err
+	}
+
+	lastBlock, err := p.cli.GetLatestBlockHeight(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to get last block, %w", err)
+	}
+
+	maxGas, err := p.cli.GetBlockGasLimit(ctx, lastBlock)
+	if err != nil {
+		return fmt.Errorf("unable to get block gas limit, %w", err)
+	}
+
+	prepared, err := p.prepareStage(ctx, stage, accounts, maxGas, gasPrice)
+	if err != nil {
+		return err
+	}
+
+	// Construct the transactions using the runtime
+	txs, err := prepared.txRuntime.ConstructTransactions(
+		prepared.runKeys,
+		prepared.runAccounts,
+		stage.totalTransactions(),
+		maxGas,
+		gasPrice,
+		p.cfg.ChainID,
+		p.cli.EstimateGas,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to construct transactions, %w", err)
+	}
+
+	metadata := &txfile.Metadata{
+		ChainID:   p.cfg.ChainID,
+		Mode:      stage.Mode,
+		CreatedAt: time.Now().UTC(),
+		Rate:      stage.Rate,
+		Duration:  stage.Duration,
+		Profile:   stage.Profile,
+		Accounts:  make([]txfile.Account, 0, len(prepared.runAccounts)),
+	}
+
+	// Replayed histories keep their send timing
+	if stage.history != nil {
+		metadata.Schedule = stage.history.Schedule(stage.HistorySpeed)
+	}
+
+	for _, account := range prepared.runAccounts {
+		metadata.Accounts = append(metadata.Accounts, txfile.Account{
+			Address:       account.GetAddress().String(),
+			AccountNumber: account.GetAccountNumber(),
+			Sequence:      account.GetSequence(),
+		})
+	}
+
+	fmt.Printf("\n💾 Exporting Transactions 💾\n\n")
+
+	if err := txfile.Write(path, metadata, txs); err != nil {
+		return fmt.Errorf("unable to export transactions, %w", err)
+	}
+
+	fmt.Printf("✅ Successfully exported %d transactions to %s\n", len(txs), path)
+
+	return nil
+}
+
+// Replay sends out the transactions exported to the file at the given path,
+// and collects their results. The transactions are sent out the same way they would
+// have been in the exported run (in a single burst, following the load profile or the schedule)
+func (p *Pipeline) Replay(ctx context.Context, path string) error {
+	fmt.Printf("\n📂 Loading Transactions 📂\n\n")
+
+	metadata, txs, err := txfile.Read(path)
+	if err != nil {
+		return fmt.Errorf("unable to load transactions, %w", err)
+	}
+
+	if len(txs) == 0 {
+		return errNoReplayTxs
+	}
+
+	fmt.Printf(
+		"✅ Successfully loaded %d %s transactions, signed for chain %s at %s\n",
+		len(txs),
+		metadata.Mode,
+		metadata.ChainID,
+		metadata.CreatedAt.Format(time.RFC3339),
+	)
+
+	// The signatures are only valid if the signer
+	// accounts on the node match the exported ones
+	if err := p.verifyAccounts(ctx, metadata.Accounts); err != nil {
+		return err
+	}
+
+	stage := Stage{
+		Name:         metadata.Mode,
+		Mode:         metadata.Mode,
+		Transactions: uint64(len(txs)),
+		Rate:         metadata.Rate,
+		Duration:     metadata.Duration,
+		Profile:      metadata.Profile,
+	}
+
+	var runResult *collector.RunResult
+
+	switch {
+	case len(metadata.Schedule) > 0:
+		runResult, err = p.sendStream(
+			ctx,
+			scheduleProfile(metadata.Schedule),
+			replaySource(txs),
+			uint64(len(txs)),
+			metadata.Schedule,
+		)
+	case !stage.isRateControlled():
+		runResult, err = p.sendBurst(ctx, txs)
+	default:
+		loadProfile, profileErr := stage.loadProfile()
+		if profileErr != nil {
+			return fmt.Errorf("unable to load profile, %w", profileErr)
+		}
+
+		runResult, err = p.sendStream(ctx, loadProfile, replaySource(txs), uint64(len(txs)), nil)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// Display [+ save the results]
+	return p.handleResults([]*stageResult{
+		{
+			RunResult: runResult,
+			Name:      stage.Name,
+			Mode:      stage.Mode,
+		},
+	})
+}
+
+// verifyAccounts verifies the exported accounts match the accounts on the node
+// (account number and sequence), so the exported transactions can be committed
+func (p *Pipeline) verifyAccounts(ctx context.Context, accounts []txfile.Account) error {
+	fmt.Printf("\n🔍 Verifying Accounts 🔍\n\n")
+
+	bar := progressbar.Default(int64(len(accounts)), "accounts verified")
+
+	for _, exported := range accounts {
+		account, err := p.cli.GetAccount(ctx, exported.Address)
+		if err != nil {
+			return fmt.Errorf("unable to fetch account, %w", err)
+		}
+
+		if account.GetAccountNumber() != exported.AccountNumber || account.GetSequence() != exported.Sequence {
+			return fmt.Errorf(
+				"%w, account %s has number %d and sequence %d on the node, but %d and %d were exported",
+				errAccountMismatch,
+				exported.Address,
+				account.GetAccountNumber(),
+				account.GetSequence(),
+				exported.AccountNumber,
+				exported.Sequence,
+			)
+		}
+
+		_ = bar.Add(1) //nolint:errcheck // No need to check
+	}
+
+	fmt.Printf("✅ Successfully verified %d accounts\n", len(accounts))
+
+	return nil
+}
+
+// replaySource returns a transaction source
+// that yields the given labeled transactions, in order
+func replaySource(txs []*std.Tx) batcher.TxSource {
+	index := 0
+
+	return func() (*std.Tx, common.TxLabel, error) {
+		if index >= len(txs) {
+			return nil, common.TxLabel{}, errNoReplayTxs
+		}
+
+		tx := txs[index]
+		index++
+
+		return tx, txLabel(tx), nil
+	}
+}
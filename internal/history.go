@@ -0,0 +1,227 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/supernova/internal/collector"
+	"github.com/gnolang/supernova/internal/profile"
+	"github.com/gnolang/supernova/internal/runtime"
+	"github.com/gnolang/supernova/internal/txfile"
+	"github.com/schollz/progressbar/v3"
+)
+
+// historyPhases is the maximum number of phases the history
+// replay is split into, when the results are reported per phase
+const historyPhases = 10
+
+var errInvalidBlockRange = errors.New("block range must be <from>:<to>, with 1 <= from <= to")
+
+// parseBlockRange parses the block range specification,
+// as <from>:<to> (both inclusive), or a single block height
+func parseBlockRange(spec string) (int64, int64, error) {
+	fromSpec, toSpec, found := strings.Cut(spec, ":")
+	if !found {
+		toSpec = fromSpec
+	}
+
+	from, err := strconv.ParseInt(strings.TrimSpace(fromSpec), 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w, %q", errInvalidBlockRange, spec)
+	}
+
+	to, err := strconv.ParseInt(strings.TrimSpace(toSpec), 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w, %q", errInvalidBlockRange, spec)
+	}
+
+	if from < 1 || to < from {
+		return 0, 0, fmt.Errorf("%w, %q", errInvalidBlockRange, spec)
+	}
+
+	return from, to, nil
+}
+
+// withHistory loads the source chain history of HISTORY stages.
+// The stage replays each replayable source transaction once
+func (p *Pipeline) withHistory(ctx context.Context, stage Stage) (Stage, error) {
+	if runtime.Type(stage.Mode) != runtime.History {
+		return stage, nil
+	}
+
+	fmt.Printf("\n📜 Loading History 📜\n\n")
+
+	var (
+		txs []runtime.HistoryTx
+		err error
+	)
+
+	if stage.HistoryFile != "" {
+		txs, err = loadFileHistory(stage.HistoryFile)
+	} else {
+		txs, err = p.loadChainHistory(ctx, stage)
+	}
+
+	if err != nil {
+		return Stage{}, fmt.Errorf("unable to load history, %w", err)
+	}
+
+	history := runtime.NewTxHistory(txs)
+	if history.Len() == 0 {
+		return Stage{}, runtime.ErrEmptyHistory
+	}
+
+	fmt.Printf(
+		"✅ Successfully loaded %d replayable transactions (%d unsupported messages skipped)\n",
+		history.Len(),
+		history.Skipped(),
+	)
+
+	stage.history = history
+	stage.Transactions = uint64(history.Len())
+
+	return stage, nil
+}
+
+// loadChainHistory reads the transactions committed in the stage block range
+// of the source chain. The cluster itself is the source chain, if no source URL is set
+func (p *Pipeline) loadChainHistory(ctx context.Context, stage Stage) ([]runtime.HistoryTx, error) {
+	from, to, err := parseBlockRange(stage.HistoryBlocks)
+	if err != nil {
+		return nil, err
+	}
+
+	var source collector.Client = p.cli
+
+	if stage.HistoryURL != "" {
+		source, err = newClient(stage.HistoryURL)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create source RPC client, %w", err)
+		}
+	}
+
+	var (
+		txs         = make([]runtime.HistoryTx, 0)
+		undecodable int
+		start       time.Time
+
+		bar = progressbar.Default(to-from+1, "blocks read")
+	)
+
+	for height := from; height <= to; height++ {
+		block, err := source.GetBlock(ctx, &height)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch block %d, %w", height, err)
+		}
+
+		if height == from {
+			start = block.Block.Time
+		}
+
+		for _, txBytes := range block.Block.Txs {
+			var tx std.Tx
+
+			// Transactions with messages unknown
+			// to supernova can't be decoded
+			if err := amino.Unmarshal(txBytes, &tx); err != nil {
+				undecodable++
+
+				continue
+			}
+
+			txs = append(txs, runtime.HistoryTx{
+				Tx:     &tx,
+				Offset: block.Block.Time.Sub(start),
+			})
+		}
+
+		_ = bar.Add(1) //nolint:errcheck // No need to check
+	}
+
+	if undecodable > 0 {
+		fmt.Printf("Skipped %d transactions that could not be decoded\n", undecodable)
+	}
+
+	return txs, nil
+}
+
+// loadFileHistory reads the transactions of the tx export file. Scheduled exports keep
+// their timing, other exports are spread out at their send rate (or sent out at once)
+func loadFileHistory(path string) ([]runtime.HistoryTx, error) {
+	metadata, fileTxs, err := txfile.Read(path)
+	if err != nil {
+		return nil, err
+	}
+
+	txs := make([]runtime.HistoryTx, 0, len(fileTxs))
+
+	for index, tx := range fileTxs {
+		var offset time.Duration
+
+		switch {
+		case len(metadata.Schedule) == len(fileTxs):
+			offset = metadata.Schedule[index]
+		case metadata.Rate > 0:
+			offset = time.Duration(index) * time.Second / time.Duration(metadata.Rate)
+		}
+
+		txs = append(txs, runtime.HistoryTx{
+			Tx:     tx,
+			Offset: offset,
+		})
+	}
+
+	return txs, nil
+}
+
+// scheduleProfile splits the send schedule into (up to historyPhases) equal phases,
+// so the results can be reported per phase. The phase send rate is the average one.
+// Schedules that send out everything at once have no profile
+func scheduleProfile(schedule []time.Duration) profile.Profile {
+	duration := schedule[len(schedule)-1]
+	if duration <= 0 {
+		return nil
+	}
+
+	var (
+		numPhases = max(min(historyPhases, int(duration/time.Second)), 1)
+		window    = duration / time.Duration(numPhases)
+
+		phases = make(profile.Profile, 0, numPhases)
+		next   = 0
+	)
+
+	for index := 0; index < numPhases; index++ {
+		end := window * time.Duration(index+1)
+		if index == numPhases-1 {
+			end = duration
+		}
+
+		sent := 0
+
+		for next < len(schedule) && (schedule[next] < end || index == numPhases-1) {
+			sent++
+			next++
+		}
+
+		phaseDuration := end - window*time.Duration(index)
+		rate := uint64(math.Round(float64(sent) / phaseDuration.Seconds()))
+
+		phases = append(phases, profile.Phase{
+			Name:     fmt.Sprintf("%s-%d", strings.ToLower(string(runtime.History)), index+1),
+			Shape:    profile.Constant,
+			From:     rate,
+			To:       rate,
+			Duration: phaseDuration,
+		})
+	}
+
+	return phases
+}
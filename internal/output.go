@@ -3,18 +3,137 @@ package internal
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"sort"
+	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/gnolang/supernova/internal/collector"
+	"github.com/gnolang/supernova/internal/common"
+	"github.com/gnolang/supernova/internal/distributor"
+	"github.com/gnolang/supernova/internal/query"
+	"github.com/gnolang/supernova/internal/runtime"
 )
 
+// stageResult is the result of a single run stage
+type stageResult struct {
+	*collector.RunResult
+
+	Name    string        `json:"name"`
+	Mode    string        `json:"mode"`
+	Queries *query.Result `json:"queries,omitempty"` // the query load result, if any
+}
+
+// scenarioResult is the result of a run with multiple stages
+type scenarioResult struct {
+	Stages []*stageResult `json:"stages"`
+}
+
 // displayResults displays the runtime result in the terminal
 func displayResults(result *collector.RunResult) {
 	w := tabwriter.NewWriter(os.Stdout, 10, 20, 2, ' ', 0)
 
+	if result.Partial {
+		_, _ = fmt.Fprintln(w, "\n⚠️ Partial results, the collection ended before all txs were committed")
+	}
+
 	// TPS //
 	_, _ = fmt.Fprintf(w, "\nTPS: %.2f\n", result.AverageTPS)
+	_, _ = fmt.Fprintf(w, "MPS: %.2f\n", result.AverageMPS)
+
+	// Outcome info //
+	if result.Outcomes != nil {
+		displayOutcomes(w, result.Outcomes)
+	}
+
+	// Latency info //
+	if result.Latency != nil {
+		_, _ = fmt.Fprintln(w, "\nLatency\tAverage\tP50\tP90\tP99\tMax")
+		displayLatency(w, "Commit", result.Latency.Commit)
+		displayLatency(w, "Observed", result.Latency.Observed)
+	}
+
+	// Phase info //
+	if len(result.Phases) > 0 {
+		_, _ = fmt.Fprintln(w, "\nPhase\tTarget Rate\tSent\tCommitted\tBlocks\tTPS\tAvg. Utilization")
+		for _, phase := range result.Phases {
+			_, _ = fmt.Fprintf(
+				w,
+				"%s\t%d -> %d\t%d\t%d\t%d\t%.2f\t%.2f%%\n",
+				phase.Name,
+				phase.StartRate,
+				phase.EndRate,
+				phase.Sent,
+				phase.Committed,
+				phase.Blocks,
+				phase.TPS,
+				phase.AverageUtilization,
+			)
+		}
+	}
+
+	// Endpoint info //
+	if len(result.Endpoints) > 0 {
+		_, _ = fmt.Fprintln(w, "\nEndpoint\tSent\tSucceeded\tFailed\tRejected\tNot Included\tP50 Commit\tP99 Commit")
+		for _, endpoint := range result.Endpoints {
+			_, _ = fmt.Fprintf(
+				w,
+				"%s\t%d\t%d\t%d\t%d\t%d\t%s\t%s\n",
+				endpoint.Endpoint,
+				endpoint.Outcomes.Sent,
+				endpoint.Outcomes.Succeeded,
+				endpoint.Outcomes.Failed,
+				endpoint.Outcomes.Rejected,
+				endpoint.Outcomes.NotIncluded,
+				endpoint.Latency.Commit.P50.Round(time.Millisecond),
+				endpoint.Latency.Commit.P99.Round(time.Millisecond),
+			)
+		}
+	}
+
+	// Type info //
+	if len(result.Types) > 0 {
+		_, _ = fmt.Fprintln(w, "\nType\tSent\tSucceeded\tFailed\tRejected\tNot Included\tAvg. Gas Used\tP50 Commit")
+		for _, txType := range result.Types {
+			_, _ = fmt.Fprintf(
+				w,
+				"%s\t%d\t%d\t%d\t%d\t%d\t%d\t%s\n",
+				txType.Type,
+				txType.Outcomes.Sent,
+				txType.Outcomes.Succeeded,
+				txType.Outcomes.Failed,
+				txType.Outcomes.Rejected,
+				txType.Outcomes.NotIncluded,
+				txType.AverageGasUsed,
+				txType.Latency.Commit.P50.Round(time.Millisecond),
+			)
+		}
+	}
+
+	// Payload info //
+	if len(result.Payloads) > 0 {
+		_, _ = fmt.Fprintln(
+			w,
+			"\nPayload (bytes)\tSent\tSucceeded\tFailed\tRejected\tNot Included\tAvg. Gas Used\tP50 Commit\tP99 Commit",
+		)
+		for _, payload := range result.Payloads {
+			_, _ = fmt.Fprintf(
+				w,
+				"%d\t%d\t%d\t%d\t%d\t%d\t%d\t%s\t%s\n",
+				payload.Payload,
+				payload.Outcomes.Sent,
+				payload.Outcomes.Succeeded,
+				payload.Outcomes.Failed,
+				payload.Outcomes.Rejected,
+				payload.Outcomes.NotIncluded,
+				payload.AverageGasUsed,
+				payload.Latency.Commit.P50.Round(time.Millisecond),
+				payload.Latency.Commit.P99.Round(time.Millisecond),
+			)
+		}
+	}
 
 	// Block info //
 	_, _ = fmt.Fprintln(w, "\nBlock #\tGas Used\tGas Limit\tTransactions\tUtilization")
@@ -35,8 +154,240 @@ func displayResults(result *collector.RunResult) {
 	_ = w.Flush()
 }
 
+// displayQueryResults displays the query load result in the terminal
+func displayQueryResults(result *query.Result) {
+	w := tabwriter.NewWriter(os.Stdout, 10, 20, 2, ' ', 0)
+
+	if result.Partial {
+		_, _ = fmt.Fprintln(w, "\n⚠️ Partial query results, the run was interrupted")
+	}
+
+	// QPS //
+	_, _ = fmt.Fprintf(w, "\nQPS: %.2f\n", result.QPS)
+
+	// Outcome info //
+	_, _ = fmt.Fprintln(w, "\nQuery Path\tSent\tSucceeded\tFailed\tError Rate")
+	_, _ = fmt.Fprintf(
+		w,
+		"%s\t%d\t%d\t%d\t%.2f%%\n",
+		result.Path,
+		result.Sent,
+		result.Succeeded,
+		result.Failed,
+		result.ErrorRate,
+	)
+
+	// Latency info //
+	if result.Latency != nil {
+		_, _ = fmt.Fprintln(w, "\nLatency\tAverage\tP50\tP90\tP99\tMax")
+		displayLatency(w, "Query", result.Latency)
+	}
+
+	// Error info //
+	if len(result.Errors) > 0 {
+		// Sort the errors, for a stable display
+		errTypes := make([]string, 0, len(result.Errors))
+		for errType := range result.Errors {
+			errTypes = append(errTypes, errType)
+		}
+
+		sort.Strings(errTypes)
+
+		_, _ = fmt.Fprintln(w, "\nQuery Error\tCount")
+
+		for _, errType := range errTypes {
+			_, _ = fmt.Fprintf(w, "%s\t%d\n", errType, result.Errors[errType])
+		}
+	}
+
+	_, _ = fmt.Fprintln(w, "")
+
+	_ = w.Flush()
+}
+
+// displayPlans displays the execution plan of the run stages in the terminal
+func displayPlans(plans []*stagePlan, maxGas int64, blockTime time.Duration, balance int64) {
+	w := tabwriter.NewWriter(os.Stdout, 10, 20, 2, ' ', 0)
+
+	_, _ = fmt.Fprintln(w, "\n📝 Execution Plan 📝")
+
+	if maxGas > 0 {
+		_, _ = fmt.Fprintf(w, "\nBlock Gas Limit: %d\n", maxGas)
+	}
+
+	if blockTime > 0 {
+		_, _ = fmt.Fprintf(w, "Average Block Time: %s\n", blockTime.Round(time.Millisecond))
+	}
+
+	// Transaction info //
+	_, _ = fmt.Fprintln(w, "\nStage\tMode\tTransactions\tGas / Tx\tTotal Gas\tBlocks\tDuration")
+	for _, plan := range plans {
+		_, _ = fmt.Fprintf(
+			w,
+			"%s\t%s\t%d\t%s\t%d\t%s\t%s\n",
+			plan.Name,
+			plan.Mode,
+			plan.Transactions,
+			formatPlanGas(plan.Gas),
+			plan.TotalGas,
+			formatPlanBlocks(plan.Blocks),
+			formatPlanDuration(plan.Duration),
+		)
+	}
+
+	// Funding info //
+	_, _ = fmt.Fprintln(
+		w,
+		"\nStage\tAccount Cost\tReady\tTo Fund\tUnfundable\tFunding Txs\tFunders\tPredeploy Fees\tRequired",
+	)
+
+	var required int64
+
+	for _, plan := range plans {
+		required += plan.required()
+
+		funding := plan.Funding
+		if funding == nil {
+			funding = &distributor.Plan{}
+		}
+
+		_, _ = fmt.Fprintf(
+			w,
+			"%s\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%d\n",
+			plan.Name,
+			plan.AccountCost,
+			funding.Ready,
+			funding.Funded,
+			funding.Unfundable,
+			funding.FundingTxs,
+			funding.Funders,
+			plan.PredeployFees,
+			plan.required(),
+		)
+	}
+
+	_, _ = fmt.Fprintf(
+		w,
+		"\nTotal Required: %d %s (distributor balance is %d %s)\n",
+		required,
+		common.Denomination,
+		balance,
+		common.Denomination,
+	)
+
+	if required > balance {
+		_, _ = fmt.Fprintf(
+			w,
+			"⚠️ The distributor is short %d %s, not all sub-accounts can be funded\n",
+			required-balance,
+			common.Denomination,
+		)
+	}
+
+	_, _ = fmt.Fprintln(w, "")
+
+	_ = w.Flush()
+}
+
+// formatPlanGas formats the gas of a single run tx, per runtime
+func formatPlanGas(gas map[runtime.Type]int64) string {
+	if len(gas) == 0 {
+		return "-"
+	}
+
+	// Sort the runtimes, for a stable display
+	types := make([]runtime.Type, 0, len(gas))
+	for txType := range gas {
+		types = append(types, txType)
+	}
+
+	sort.Slice(types, func(i, j int) bool {
+		return types[i] < types[j]
+	})
+
+	if len(types) == 1 {
+		return fmt.Sprintf("%d", gas[types[0]])
+	}
+
+	parts := make([]string, 0, len(types))
+	for _, txType := range types {
+		parts = append(parts, fmt.Sprintf("%s:%d", txType, gas[txType]))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// formatPlanBlocks formats the expected number of blocks, if it is known
+func formatPlanBlocks(blocks int64) string {
+	if blocks == 0 {
+		return "-"
+	}
+
+	return fmt.Sprintf("%d", blocks)
+}
+
+// formatPlanDuration formats the plan duration, if it is known
+func formatPlanDuration(duration time.Duration) string {
+	if duration == 0 {
+		return "unknown"
+	}
+
+	return duration.Round(time.Second).String()
+}
+
+// displayOutcomes displays the transaction outcomes,
+// along with the error breakdown, if any
+func displayOutcomes(w io.Writer, outcomes *collector.OutcomeResult) {
+	_, _ = fmt.Fprintln(w, "\nSent\tSucceeded\tFailed\tRejected\tNot Included")
+	_, _ = fmt.Fprintf(
+		w,
+		"%d\t%d\t%d\t%d\t%d\n",
+		outcomes.Sent,
+		outcomes.Succeeded,
+		outcomes.Failed,
+		outcomes.Rejected,
+		outcomes.NotIncluded,
+	)
+
+	if len(outcomes.Errors) == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintln(w, "\nOutcome\tError\tCount")
+
+	for _, status := range []collector.TxStatus{collector.Failed, collector.Rejected} {
+		errs := outcomes.Errors[status]
+
+		// Sort the errors, for a stable display
+		errTypes := make([]string, 0, len(errs))
+		for errType := range errs {
+			errTypes = append(errTypes, errType)
+		}
+
+		sort.Strings(errTypes)
+
+		for _, errType := range errTypes {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%d\n", status, errType, errs[errType])
+		}
+	}
+}
+
+// displayLatency displays a single latency result row
+func displayLatency(w io.Writer, name string, latency *collector.LatencyResult) {
+	_, _ = fmt.Fprintf(
+		w,
+		"%s\t%s\t%s\t%s\t%s\t%s\n",
+		name,
+		latency.Average.Round(time.Millisecond),
+		latency.P50.Round(time.Millisecond),
+		latency.P90.Round(time.Millisecond),
+		latency.P99.Round(time.Millisecond),
+		latency.Max.Round(time.Millisecond),
+	)
+}
+
 // saveResults saves the runtime results to a file
-func saveResults(result *collector.RunResult, path string) error {
+func saveResults(result any, path string) error {
 	// Marshal the results
 	resultJSON, err := json.Marshal(result)
 	if err != nil {
@@ -3,6 +3,7 @@ package internal
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/gnolang/gno/tm2/pkg/crypto"
@@ -11,12 +12,19 @@ import (
 	"github.com/gnolang/supernova/internal/batcher"
 	"github.com/gnolang/supernova/internal/client"
 	"github.com/gnolang/supernova/internal/collector"
+	"github.com/gnolang/supernova/internal/common"
 	"github.com/gnolang/supernova/internal/distributor"
+	"github.com/gnolang/supernova/internal/profile"
+	"github.com/gnolang/supernova/internal/query"
 	"github.com/gnolang/supernova/internal/runtime"
 	"github.com/gnolang/supernova/internal/signer"
 	"github.com/schollz/progressbar/v3"
 )
 
+// sentBufferSize is the buffer size of the channel
+// that streams sent transactions to the collector
+const sentBufferSize = 10_000
+
 type pipelineClient interface {
 	distributor.Client
 	batcher.Client
@@ -28,43 +36,76 @@ type pipelineClient interface {
 type Pipeline struct {
 	cfg *Config        // the run configuration
 	cli pipelineClient // HTTP client connection
+
+	lanes   []batcher.Lane // the lanes transactions are sent over
+	targets []query.Target // the endpoints queries are sent to
 }
 
 // NewPipeline creates a new pipeline instance
 func NewPipeline(cfg *Config) (*Pipeline, error) {
 	var (
-		cli *client.Client
-		err error
+		endpoints = cfg.endpoints()
+		primary   = endpoints[0]
 	)
 
-	// Check which kind of client to create
-	if httpRegex.MatchString(cfg.URL) {
-		cli, err = client.NewHTTPClient(cfg.URL)
-	} else {
-		cli, err = client.NewWSClient(cfg.URL)
-	}
-
+	cli, err := newClient(primary)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create RPC client, %w", err)
 	}
 
+	// Each send lane has its own connection, and the lanes
+	// are spread across the endpoints in a round-robin fashion.
+	// There is at least one lane per endpoint
+	var (
+		numLanes = max(int(cfg.Concurrency), len(endpoints))
+		lanes    = []batcher.Lane{{Client: cli, Endpoint: primary}}
+		targets  = []query.Target{{Client: cli, Endpoint: primary}}
+	)
+
+	for i := 1; i < numLanes; i++ {
+		endpoint := endpoints[i%len(endpoints)]
+
+		laneCli, err := newClient(endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create RPC client for %s, %w", endpoint, err)
+		}
+
+		lanes = append(lanes, batcher.Lane{
+			Client:   laneCli,
+			Endpoint: endpoint,
+		})
+
+		// The query load shares the lane connections
+		targets = append(targets, query.Target{
+			Client:   laneCli,
+			Endpoint: endpoint,
+		})
+	}
+
 	return &Pipeline{
-		cfg: cfg,
-		cli: cli,
+		cfg:     cfg,
+		cli:     cli,
+		lanes:   lanes,
+		targets: targets,
 	}, nil
 }
 
-// Execute runs the entire pipeline process
-func (p *Pipeline) Execute(ctx context.Context) error {
-	var (
-		mode = runtime.Type(p.cfg.Mode)
+// newClient creates a new RPC client, based on the URL scheme
+func newClient(url string) (*client.Client, error) {
+	// Check which kind of client to create
+	if httpRegex.MatchString(url) {
+		return client.NewHTTPClient(url)
+	}
 
-		txBatcher   = batcher.NewBatcher(ctx, p.cli)
-		txCollector = collector.NewCollector(ctx, p.cli)
-		txRuntime   = runtime.GetRuntime(ctx, mode)
-	)
+	return client.NewWSClient(url)
+}
 
-	// Initialize the accounts for the runtime
+// Execute runs the entire pipeline process.
+// If the context is canceled mid-run, the current stage is stopped,
+// and the results gathered so far are handled as partial results.
+// Dry runs only display the execution plan, without broadcasting anything
+func (p *Pipeline) Execute(ctx context.Context) error {
+	// Initialize the accounts for the run
 	accounts := p.initializeAccounts()
 
 	gasPrice, err := p.cli.FetchGasPrice(ctx)
@@ -81,85 +122,516 @@ func (p *Pipeline) Execute(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("unable to get block gas limit, %w", err)
 	}
+
+	stages := p.cfg.stages()
+
+	// A dry run only plans the stages, without sending out any transactions
+	if p.cfg.DryRun {
+		return p.plan(ctx, stages, accounts, lastBlock, maxGas, gasPrice)
+	}
+
+	// Execute the stages, one after another
+	results := make([]*stageResult, 0, len(stages))
+
+	for index, stage := range stages {
+		if len(stages) > 1 {
+			fmt.Printf("\n🎬 Stage %d / %d: %s (%s) 🎬\n", index+1, len(stages), stage.Name, stage.Mode)
+		}
+
+		result, err := p.executeStage(ctx, stage, accounts, maxGas, gasPrice)
+		if err != nil && ctx.Err() == nil {
+			return fmt.Errorf("unable to execute stage %s, %w", stage.Name, err)
+		}
+
+		if result != nil {
+			results = append(results, result)
+		}
+
+		if ctx.Err() != nil {
+			fmt.Printf("\n⚠️ Run interrupted during stage %s ⚠️\n", stage.Name)
+
+			break
+		}
+	}
+
+	if len(results) == 0 {
+		return fmt.Errorf("run interrupted before any results were gathered, %w", ctx.Err())
+	}
+
+	// Display [+ save the results]
+	return p.handleResults(results)
+}
+
+// Reclaim sweeps the remaining funds of the sub-accounts
+// back to the distributor (account 0 in the mnemonic)
+func (p *Pipeline) Reclaim(ctx context.Context) error {
+	// Initialize the run accounts
+	accounts := p.initializeAccounts()
+
+	gasPrice, err := p.cli.FetchGasPrice(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = distributor.NewDistributor(ctx, p.cli).Reclaim(
+		accounts[0].PubKey().Address(),
+		accounts[1:],
+		p.cfg.ChainID,
+		gasPrice,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to reclaim funds, %w", err)
+	}
+
+	return nil
+}
+
+// executeStage prepares the stage runtime and funds the sub-accounts,
+// after which the stage transactions are sent out, and their results collected.
+// The stage query load, if any, runs alongside the transactions
+func (p *Pipeline) executeStage(
+	ctx context.Context,
+	stage Stage,
+	accounts []crypto.PrivKey,
+	maxGas int64,
+	gasPrice std.GasPrice,
+) (*stageResult, error) {
+	result := &stageResult{
+		Name: stage.Name,
+		Mode: stage.Mode,
+	}
+
+	if stage.isQueryOnly() {
+		queryResult, err := p.executeQueries(ctx, stage, stage.QueryRealm)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Queries = queryResult
+
+		return result, nil
+	}
+
+	runResult, queryResult, err := p.executeTransactions(ctx, stage, accounts, maxGas, gasPrice)
+	if err != nil {
+		return nil, err
+	}
+
+	result.RunResult = runResult
+	result.Queries = queryResult
+
+	return result, nil
+}
+
+// executeQueries runs the stage query load against the given realm, for the stage duration
+func (p *Pipeline) executeQueries(ctx context.Context, stage Stage, realm string) (*query.Result, error) {
+	queryCfg, err := stage.queryConfig(realm)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("\n🔎 Running Query Load 🔎\n\n")
+	fmt.Printf("Querying %s over %s for %s...\n", realm, queryCfg.Path, stage.Duration)
+
+	queryCtx, cancelFn := context.WithTimeout(ctx, stage.Duration)
+	defer cancelFn()
+
+	result := query.NewLoader(queryCfg, p.targets...).Run(queryCtx)
+	result.Partial = ctx.Err() != nil
+
+	fmt.Printf("✅ Successfully sent %d queries\n", result.Sent)
+
+	return result, nil
+}
+
+// preparedStage is a stage that is ready to send out
+// its transactions, using the funded run accounts
+type preparedStage struct {
+	txRuntime   runtime.Runtime
+	runKeys     []crypto.PrivKey
+	runAccounts []std.Account
+}
+
+// prepareStage prepares the stage runtime by pre-deploying
+// any pending transactions, and funds the sub-accounts for the stage
+func (p *Pipeline) prepareStage(
+	ctx context.Context,
+	stage Stage,
+	accounts []crypto.PrivKey,
+	maxGas int64,
+	gasPrice std.GasPrice,
+) (*preparedStage, error) {
+	runtimeCfg, err := stage.runtimeConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	txRuntime := runtime.GetRuntime(ctx, runtime.Type(stage.Mode), runtimeCfg)
+
+	// The stage transactions are spread evenly across the sub-accounts,
+	// so each sub-account only needs to cover its own share.
+	// The distribution fails if any of the sub-accounts can't be funded
+	accountTxs := (stage.totalTransactions() + p.cfg.SubAccounts - 1) / p.cfg.SubAccounts
+
 	// Predeploy any pending transactions
-	estimatedGas, err := prepareRuntime(
+	runtimeCost, err := prepareRuntime(
 		ctx,
-		mode,
 		accounts[0],
 		p.cfg.ChainID,
 		p.cli,
 		txRuntime,
 		maxGas,
 		gasPrice,
-		p.cfg.Transactions,
+		accountTxs,
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Extract the addresses
-	addresses := make([]crypto.Address, 0, len(accounts[1:]))
-	for _, account := range accounts[1:] {
-		addresses = append(addresses, account.PubKey().Address())
-	}
+	runtimeCost = withMargin(runtimeCost, p.cfg.FundingMargin)
 
 	// Distribute the funds to sub-accounts
 	runAccounts, err := distributor.NewDistributor(ctx, p.cli).Distribute(
 		accounts[0],
-		addresses,
+		accounts[1:],
 		p.cfg.ChainID,
 		gasPrice,
-		estimatedGas,
+		maxGas,
+		runtimeCost,
 	)
 	if err != nil {
-		return fmt.Errorf("unable to distribute funds, %w", err)
+		return nil, fmt.Errorf("unable to distribute funds, %w", err)
 	}
 
 	// Find which keys belong to the run accounts (not all initial accounts are run accounts)
+	return &preparedStage{
+		txRuntime:   txRuntime,
+		runKeys:     runKeys(accounts[1:], runAccounts),
+		runAccounts: runAccounts,
+	}, nil
+}
+
+// executeTransactions prepares the stage runtime and funds the sub-accounts,
+// after which the stage transactions are sent out, and their results collected
+func (p *Pipeline) executeTransactions(
+	ctx context.Context,
+	stage Stage,
+	accounts []crypto.PrivKey,
+	maxGas int64,
+	gasPrice std.GasPrice,
+) (*collector.RunResult, *query.Result, error) {
+	stage, err := p.withHistory(ctx, stage)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prepared, err := p.prepareStage(ctx, stage, accounts, maxGas, gasPrice)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Start the query load alongside the transactions, if any
+	var (
+		queryResult *query.Result
+		stopQueries = func() {}
+	)
+
+	if stage.QueryConcurrency > 0 {
+		realm := stage.QueryRealm
+		if realm == "" {
+			realm = runtime.DeployedRealm(prepared.txRuntime)
+		}
+
+		if realm == "" {
+			return nil, nil, errMissingQueryRealm
+		}
+
+		queryCfg, err := stage.queryConfig(realm)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var (
+			queryCtx, cancelFn = context.WithCancel(ctx)
+			wg                 sync.WaitGroup
+		)
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			queryResult = query.NewLoader(queryCfg, p.targets...).Run(queryCtx)
+			queryResult.Partial = ctx.Err() != nil
+		}()
+
+		stopQueries = func() {
+			cancelFn()
+			wg.Wait()
+		}
+	}
+
+	// Send out the transactions, and collect the results
+	var runResult *collector.RunResult
+
+	switch {
+	case stage.history != nil:
+		runResult, err = p.executeHistory(ctx, stage, prepared, maxGas, gasPrice)
+	case !stage.isRateControlled():
+		runResult, err = p.executeBurst(ctx, stage, prepared, maxGas, gasPrice)
+	default:
+		runResult, err = p.executeStream(ctx, stage, prepared, maxGas, gasPrice)
+	}
+
+	// The query load lasts as long as the transaction workload
+	stopQueries()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return runResult, queryResult, nil
+}
+
+// runKeys returns the keys that belong to the given run accounts
+func runKeys(keys []crypto.PrivKey, runAccounts []std.Account) []crypto.PrivKey {
 	runKeys := make([]crypto.PrivKey, 0, len(runAccounts))
 
 	for _, runAccount := range runAccounts {
-		for _, account := range accounts[1:] {
-			if account.PubKey().Address() == runAccount.GetAddress() {
-				runKeys = append(runKeys, account)
+		for _, key := range keys {
+			if key.PubKey().Address() == runAccount.GetAddress() {
+				runKeys = append(runKeys, key)
 			}
 		}
 	}
 
+	return runKeys
+}
+
+// executeBurst constructs all run transactions beforehand,
+// and sends them out in batches as fast as possible
+func (p *Pipeline) executeBurst(
+	ctx context.Context,
+	stage Stage,
+	prepared *preparedStage,
+	maxGas int64,
+	gasPrice std.GasPrice,
+) (*collector.RunResult, error) {
 	// Construct the transactions using the runtime
-	txs, err := txRuntime.ConstructTransactions(
-		runKeys,
-		runAccounts,
-		p.cfg.Transactions,
+	txs, err := prepared.txRuntime.ConstructTransactions(
+		prepared.runKeys,
+		prepared.runAccounts,
+		stage.Transactions,
 		maxGas,
 		gasPrice,
 		p.cfg.ChainID,
 		p.cli.EstimateGas,
 	)
 	if err != nil {
-		return fmt.Errorf("unable to construct transactions, %w", err)
+		return nil, fmt.Errorf("unable to construct transactions, %w", err)
 	}
 
+	return p.sendBurst(ctx, txs)
+}
+
+// sendBurst sends out the signed transactions in batches
+// as fast as possible, and collects their results
+func (p *Pipeline) sendBurst(ctx context.Context, txs []*std.Tx) (*collector.RunResult, error) {
+	var (
+		txBatcher   = batcher.NewBatcher(ctx, p.cli, p.lanes...)
+		txCollector = collector.NewCollector(ctx, p.cli, p.cfg.collectorConfig())
+	)
+
 	// Send the signed transactions in batches
 	batchStart := time.Now()
 
-	batchResult, err := txBatcher.BatchTransactions(txs, int(p.cfg.BatchSize))
+	batchResult, err := txBatcher.BatchTransactions(txs, txLabels(txs), int(p.cfg.BatchSize))
 	if err != nil {
-		return fmt.Errorf("unable to batch transactions %w", err)
+		return nil, fmt.Errorf("unable to batch transactions %w", err)
 	}
 
 	// Collect the transaction results
 	runResult, err := txCollector.GetRunResult(
-		batchResult.TxHashes,
+		batchResult.SentTxs,
 		batchResult.StartBlock,
 		batchStart,
 	)
 	if err != nil {
-		return fmt.Errorf("unable to collect transactions, %w", err)
+		return nil, fmt.Errorf("unable to collect transactions, %w", err)
 	}
 
-	// Display [+ save the results]
-	return p.handleResults(runResult)
+	return runResult, nil
+}
+
+// executeStream generates, signs and sends out the run transactions following
+// the configured load profile, while the results are collected concurrently
+func (p *Pipeline) executeStream(
+	ctx context.Context,
+	stage Stage,
+	prepared *preparedStage,
+	maxGas int64,
+	gasPrice std.GasPrice,
+) (*collector.RunResult, error) {
+	loadProfile, err := stage.loadProfile()
+	if err != nil {
+		return nil, fmt.Errorf("unable to load profile, %w", err)
+	}
+
+	// Prepare the transaction generator using the runtime
+	generator, err := prepared.txRuntime.NewGenerator(
+		prepared.runKeys,
+		prepared.runAccounts,
+		maxGas,
+		gasPrice,
+		p.cfg.ChainID,
+		p.cli.EstimateGas,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create transaction generator, %w", err)
+	}
+
+	return p.sendStream(ctx, loadProfile, generatorSource(generator), stage.totalTransactions(), nil)
+}
+
+// executeHistory generates, signs and sends out the replayed history transactions,
+// keeping the relative source timing (compressed by the speed factor),
+// while the results are collected concurrently
+func (p *Pipeline) executeHistory(
+	ctx context.Context,
+	stage Stage,
+	prepared *preparedStage,
+	maxGas int64,
+	gasPrice std.GasPrice,
+) (*collector.RunResult, error) {
+	// Prepare the transaction generator using the runtime
+	generator, err := prepared.txRuntime.NewGenerator(
+		prepared.runKeys,
+		prepared.runAccounts,
+		maxGas,
+		gasPrice,
+		p.cfg.ChainID,
+		p.cli.EstimateGas,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create transaction generator, %w", err)
+	}
+
+	schedule := stage.history.Schedule(stage.HistorySpeed)
+
+	return p.sendStream(ctx, scheduleProfile(schedule), generatorSource(generator), uint64(len(schedule)), schedule)
+}
+
+// generatorSource returns a transaction source
+// that yields the labeled transactions of the generator
+func generatorSource(generator *runtime.Generator) batcher.TxSource {
+	return func() (*std.Tx, common.TxLabel, error) {
+		tx, err := generator.Next()
+		if err != nil {
+			return nil, common.TxLabel{}, err
+		}
+
+		return tx, txLabel(tx), nil
+	}
+}
+
+// txLabel returns the runtime label of the transaction
+func txLabel(tx *std.Tx) common.TxLabel {
+	return common.TxLabel{
+		Type:    runtime.TxType(tx).String(),
+		Payload: runtime.TxPayloadSize(tx),
+	}
+}
+
+// txLabels returns the runtime labels of the transactions
+func txLabels(txs []*std.Tx) []common.TxLabel {
+	labels := make([]common.TxLabel, 0, len(txs))
+
+	for _, tx := range txs {
+		labels = append(labels, txLabel(tx))
+	}
+
+	return labels
+}
+
+// sendStream sends out the transactions of the source following the load profile
+// (or the send schedule, if any), up until the given limit,
+// while the results are collected concurrently
+func (p *Pipeline) sendStream(
+	ctx context.Context,
+	loadProfile profile.Profile,
+	source batcher.TxSource,
+	limit uint64,
+	schedule []time.Duration,
+) (*collector.RunResult, error) {
+	startBlock, err := p.cli.GetLatestBlockHeight(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch latest block, %w", err)
+	}
+
+	// The collector is stopped if the stream fails
+	collectCtx, cancelFn := context.WithCancel(ctx)
+	defer cancelFn()
+
+	var (
+		txBatcher   = batcher.NewBatcher(ctx, p.cli, p.lanes...)
+		txCollector = collector.NewCollector(collectCtx, p.cli, p.cfg.collectorConfig())
+
+		sentTxs     = make(chan common.SentTx, sentBufferSize)
+		streamStart = time.Now()
+
+		runResult  *collector.RunResult
+		collectErr error
+		wg         sync.WaitGroup
+	)
+
+	// Start collecting the results alongside the stream
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		runResult, collectErr = txCollector.StreamRunResult(
+			sentTxs,
+			startBlock,
+			streamStart,
+			loadProfile,
+		)
+	}()
+
+	// Stream the transactions
+	streamErr := txBatcher.StreamTransactions(
+		source,
+		batcher.StreamConfig{
+			Profile:   loadProfile,
+			Limit:     limit,
+			BatchSize: int(p.cfg.BatchSize),
+			Schedule:  schedule,
+		},
+		sentTxs,
+	)
+	if streamErr != nil {
+		cancelFn()
+	}
+
+	// An interrupted stream still yields the results collected so far
+	if ctx.Err() != nil {
+		streamErr = nil
+	}
+
+	fmt.Printf("\n📊 Collecting Results 📊\n\n")
+	fmt.Printf("Waiting for the remaining transactions to be committed...\n")
+
+	wg.Wait()
+
+	if streamErr != nil {
+		return nil, fmt.Errorf("unable to stream transactions, %w", streamErr)
+	}
+
+	if collectErr != nil {
+		return nil, fmt.Errorf("unable to collect transactions, %w", collectErr)
+	}
+
+	return runResult, nil
 }
 
 // initializeAccounts initializes the accounts needed for the stress test run
@@ -187,9 +659,21 @@ func (p *Pipeline) initializeAccounts() []crypto.PrivKey {
 
 // handleResults displays the results in the terminal,
 // and saves them to disk if an output path was specified
-func (p *Pipeline) handleResults(runResult *collector.RunResult) error {
+func (p *Pipeline) handleResults(results []*stageResult) error {
 	// Display the results in the terminal
-	displayResults(runResult)
+	for _, result := range results {
+		if len(results) > 1 {
+			fmt.Printf("\n📋 Stage %s (%s) 📋\n", result.Name, result.Mode)
+		}
+
+		if result.RunResult != nil {
+			displayResults(result.RunResult)
+		}
+
+		if result.Queries != nil {
+			displayQueryResults(result.Queries)
+		}
+	}
 
 	// Check if the results need to be saved to disk
 	if p.cfg.Output == "" {
@@ -199,7 +683,18 @@ func (p *Pipeline) handleResults(runResult *collector.RunResult) error {
 
 	fmt.Printf("\n💾 Saving Results 💾\n\n")
 
-	if err := saveResults(runResult, p.cfg.Output); err != nil {
+	// A run with a single stage saves the stage result directly,
+	// and runs without queries only save the transaction result
+	var output any = &scenarioResult{Stages: results}
+	if len(results) == 1 {
+		output = results[0]
+
+		if results[0].Queries == nil {
+			output = results[0].RunResult
+		}
+	}
+
+	if err := saveResults(output, p.cfg.Output); err != nil {
 		return fmt.Errorf("unable to save results, %w", err)
 	}
 
@@ -208,11 +703,17 @@ func (p *Pipeline) handleResults(runResult *collector.RunResult) error {
 	return nil
 }
 
-// prepareRuntime prepares the runtime by pre-deploying
-// any pending transactions
+// withMargin adds the safety margin (percentage) to the cost
+func withMargin(cost std.Coin, margin uint64) std.Coin {
+	cost.Amount += cost.Amount * int64(margin) / 100
+
+	return cost
+}
+
+// prepareRuntime prepares the runtime by pre-deploying any pending transactions,
+// and calculates the cost of a single sub-account sending out the given number of transactions
 func prepareRuntime(
 	ctx context.Context,
-	mode runtime.Type,
 	deployerKey crypto.PrivKey,
 	chainID string,
 	cli pipelineClient,
@@ -229,13 +730,7 @@ func prepareRuntime(
 
 	signCB := runtime.SignTransactionsCb(chainID, deployer, deployerKey)
 
-	if mode != runtime.RealmCall {
-		return txRuntime.CalculateRuntimeCosts(deployer, cli.EstimateGas, signCB, currentMaxGas, gasPrice, transactions)
-	}
-
-	fmt.Printf("\n✨ Starting Predeployment Procedure ✨\n\n")
-
-	// Get the predeploy transactions
+	// Get the predeploy transactions, if any
 	predeployTxs, err := txRuntime.Initialize(
 		deployer,
 		signCB,
@@ -247,6 +742,12 @@ func prepareRuntime(
 		return std.Coin{}, fmt.Errorf("unable to initialize runtime, %w", err)
 	}
 
+	if len(predeployTxs) == 0 {
+		return txRuntime.CalculateRuntimeCosts(deployer, cli.EstimateGas, signCB, currentMaxGas, gasPrice, transactions)
+	}
+
+	fmt.Printf("\n✨ Starting Predeployment Procedure ✨\n\n")
+
 	bar := progressbar.Default(int64(len(predeployTxs)), "predeployed txs")
 
 	// Execute the predeploy transactions
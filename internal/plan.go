@@ -0,0 +1,288 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/supernova/internal/common"
+	"github.com/gnolang/supernova/internal/distributor"
+	"github.com/gnolang/supernova/internal/runtime"
+)
+
+// blockTimeWindow is the number of latest blocks
+// the average block time is calculated over
+const blockTimeWindow = 10
+
+// stagePlan is the execution plan of a single run stage
+type stagePlan struct {
+	Name string
+	Mode string
+
+	Predeploy     int   // the number of predeploy txs
+	PredeployFees int64 // the fees of the predeploy txs
+
+	AccountCost int64             // the funds a single sub-account needs for the stage
+	Funding     *distributor.Plan // the sub-account funding plan, if any
+
+	Transactions int                    // the number of run txs
+	Gas          map[runtime.Type]int64 // the gas wanted of a single run tx, per runtime
+	TotalGas     int64                  // the gas wanted of all run txs
+
+	Blocks   int64         // the expected number of blocks the run txs fill, if the block gas is limited
+	Duration time.Duration // the expected duration of the stage, if known
+}
+
+// required returns the funds the distributor needs for the stage
+func (s *stagePlan) required() int64 {
+	required := s.PredeployFees
+
+	if s.Funding != nil {
+		required += s.Funding.Required
+	}
+
+	return required
+}
+
+// plan calculates and displays the execution plan of the run stages.
+// Everything up to the broadcast is executed (the transactions are simulated,
+// constructed and signed), but no transactions are sent out, and no funds are spent
+func (p *Pipeline) plan(
+	ctx context.Context,
+	stages []Stage,
+	accounts []crypto.PrivKey,
+	lastBlock int64,
+	maxGas int64,
+	gasPrice std.GasPrice,
+) error {
+	blockTime, err := p.averageBlockTime(ctx, lastBlock)
+	if err != nil {
+		return err
+	}
+
+	distributorAccount, err := p.cli.GetAccount(ctx, accounts[0].PubKey().Address().String())
+	if err != nil {
+		return fmt.Errorf("unable to fetch distributor account, %w", err)
+	}
+
+	plans := make([]*stagePlan, 0, len(stages))
+
+	for index, stage := range stages {
+		if len(stages) > 1 {
+			fmt.Printf("\n🎬 Stage %d / %d: %s (%s) 🎬\n", index+1, len(stages), stage.Name, stage.Mode)
+		}
+
+		plan, err := p.planStage(ctx, stage, accounts, maxGas, gasPrice, blockTime)
+		if err != nil {
+			return fmt.Errorf("unable to plan stage %s, %w", stage.Name, err)
+		}
+
+		plans = append(plans, plan)
+	}
+
+	displayPlans(plans, maxGas, blockTime, distributorAccount.Coins.AmountOf(common.Denomination))
+
+	return nil
+}
+
+// planStage calculates the execution plan of a single stage.
+// The predeploy txs are prepared, the sub-account funding is planned,
+// and the stage transactions are constructed and signed, without being sent out
+func (p *Pipeline) planStage(
+	ctx context.Context,
+	stage Stage,
+	accounts []crypto.PrivKey,
+	maxGas int64,
+	gasPrice std.GasPrice,
+	blockTime time.Duration,
+) (*stagePlan, error) {
+	plan := &stagePlan{
+		Name: stage.Name,
+		Mode: stage.Mode,
+		Gas:  make(map[runtime.Type]int64),
+	}
+
+	if stage.isQueryOnly() {
+		plan.Duration = stage.Duration
+
+		return plan, nil
+	}
+
+	stage, err := p.withHistory(ctx, stage)
+	if err != nil {
+		return nil, err
+	}
+
+	runtimeCfg, err := stage.runtimeConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	txRuntime := runtime.GetRuntime(ctx, runtime.Type(stage.Mode), runtimeCfg)
+
+	deployer, err := p.cli.GetAccount(ctx, accounts[0].PubKey().Address().String())
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch deployer account, %w", err)
+	}
+
+	signCB := runtime.SignTransactionsCb(p.cfg.ChainID, deployer, accounts[0])
+
+	// Prepare the predeploy transactions, without deploying them
+	predeployTxs, err := txRuntime.Initialize(deployer, signCB, p.cli.EstimateGas, maxGas, gasPrice)
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize runtime, %w", err)
+	}
+
+	plan.Predeploy = len(predeployTxs)
+
+	for _, tx := range predeployTxs {
+		plan.PredeployFees += tx.Fee.GasFee.Amount
+	}
+
+	// The run transactions are simulated from the distributor account,
+	// since the sub-accounts might not be funded (or even exist) yet
+	var (
+		gas        = newGasRecorder(p.cli.EstimateGas)
+		accountTxs = (stage.totalTransactions() + p.cfg.SubAccounts - 1) / p.cfg.SubAccounts
+	)
+
+	runtimeCost, err := txRuntime.CalculateRuntimeCosts(deployer, gas.estimate, signCB, maxGas, gasPrice, accountTxs)
+	if err != nil {
+		if len(predeployTxs) > 0 {
+			return nil, fmt.Errorf(
+				"unable to simulate the run transactions, which rely on the predeploy transactions "+
+					"that are not deployed in a dry run, %w",
+				err,
+			)
+		}
+
+		return nil, err
+	}
+
+	runtimeCost = withMargin(runtimeCost, p.cfg.FundingMargin)
+	plan.AccountCost = runtimeCost.Amount
+
+	// Plan the sub-account funding
+	funding, err := distributor.NewDistributor(ctx, p.cli).Plan(
+		accounts[0],
+		accounts[1:],
+		p.cfg.ChainID,
+		gasPrice,
+		maxGas,
+		runtimeCost,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to plan fund distribution, %w", err)
+	}
+
+	plan.Funding = funding
+
+	// Construct and sign the stage transactions, using the recorded gas estimates
+	txs, err := txRuntime.ConstructTransactions(
+		runKeys(accounts[1:], funding.Accounts),
+		funding.Accounts,
+		stage.totalTransactions(),
+		maxGas,
+		gasPrice,
+		p.cfg.ChainID,
+		gas.replay,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to construct transactions, %w", err)
+	}
+
+	plan.Transactions = len(txs)
+
+	for _, tx := range txs {
+		plan.Gas[runtime.TxType(tx)] = tx.Fee.GasWanted
+		plan.TotalGas += tx.Fee.GasWanted
+	}
+
+	// The run transactions fill up at least this many blocks
+	if maxGas > 0 {
+		plan.Blocks = (plan.TotalGas + maxGas - 1) / maxGas
+	}
+
+	plan.Duration = time.Duration(plan.Blocks) * blockTime
+
+	// Replayed histories last at least as long as their schedule
+	if stage.history != nil {
+		schedule := stage.history.Schedule(stage.HistorySpeed)
+
+		plan.Duration = max(plan.Duration, schedule[len(schedule)-1])
+	}
+
+	// Rate-controlled stages last at least as long as their load profile
+	if stage.isRateControlled() {
+		loadProfile, err := stage.loadProfile()
+		if err != nil {
+			return nil, fmt.Errorf("unable to load profile, %w", err)
+		}
+
+		plan.Duration = max(plan.Duration, loadProfile.Duration())
+	}
+
+	return plan, nil
+}
+
+// averageBlockTime calculates the average block time over the latest blocks.
+// If there are not enough blocks, the block time is unknown (0)
+func (p *Pipeline) averageBlockTime(ctx context.Context, lastBlock int64) (time.Duration, error) {
+	firstBlock := max(lastBlock-blockTimeWindow, 1)
+	if firstBlock >= lastBlock {
+		return 0, nil
+	}
+
+	first, err := p.cli.GetBlock(ctx, &firstBlock)
+	if err != nil {
+		return 0, fmt.Errorf("unable to fetch block, %w", err)
+	}
+
+	last, err := p.cli.GetBlock(ctx, &lastBlock)
+	if err != nil {
+		return 0, fmt.Errorf("unable to fetch block, %w", err)
+	}
+
+	elapsed := last.Block.Time.Sub(first.Block.Time)
+
+	return elapsed / time.Duration(lastBlock-firstBlock), nil
+}
+
+// gasRecorder records the estimated gas of the run transactions, per runtime,
+// so the transactions of sub-accounts that are not funded yet can be constructed
+type gasRecorder struct {
+	estimateFn runtime.EstimateGasFn
+	gas        map[runtime.Type]int64
+}
+
+// newGasRecorder creates a new gas recorder, using the given estimation
+func newGasRecorder(estimateFn runtime.EstimateGasFn) *gasRecorder {
+	return &gasRecorder{
+		estimateFn: estimateFn,
+		gas:        make(map[runtime.Type]int64),
+	}
+}
+
+// estimate estimates the gas of the transaction, and records it
+func (r *gasRecorder) estimate(ctx context.Context, tx *std.Tx) (int64, error) {
+	gas, err := r.estimateFn(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	r.gas[runtime.TxType(tx)] = gas
+
+	return gas, nil
+}
+
+// replay returns the recorded gas of the transaction runtime.
+// Transactions of runtimes that were not recorded are estimated
+func (r *gasRecorder) replay(ctx context.Context, tx *std.Tx) (int64, error) {
+	if gas, ok := r.gas[runtime.TxType(tx)]; ok {
+		return gas, nil
+	}
+
+	return r.estimateFn(ctx, tx)
+}
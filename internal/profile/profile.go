@@ -0,0 +1,242 @@
+package profile
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	errInvalidPhase    = errors.New("invalid profile phase")
+	errInvalidShape    = errors.New("invalid profile phase shape")
+	errInvalidRate     = errors.New("invalid profile phase rate")
+	errInvalidDuration = errors.New("invalid profile phase duration")
+	errInvalidStep     = errors.New("invalid profile step increment")
+	errEmptyProfile    = errors.New("empty load profile")
+)
+
+// Shape is the load shape of a profile phase
+type Shape string
+
+const (
+	Constant Shape = "constant"
+	Ramp     Shape = "ramp"
+	Step     Shape = "step"
+	Spike    Shape = "spike"
+)
+
+const (
+	phaseSeparator = ","
+	paramSeparator = ":"
+
+	// steps is the staircase shorthand, which expands into multiple step phases
+	steps = "steps"
+)
+
+// Phase is a single load profile phase, during which the
+// send rate changes linearly from the start to the end rate
+type Phase struct {
+	Name     string        `json:"name"`
+	Shape    Shape         `json:"shape"`
+	From     uint64        `json:"startRate"` // the send rate at the start of the phase (txs / s)
+	To       uint64        `json:"endRate"`   // the send rate at the end of the phase (txs / s)
+	Duration time.Duration `json:"duration"`  // the duration of the phase
+}
+
+// Due returns the number of transactions that are due
+// to be sent out, the given time after the phase start
+func (p Phase) Due(elapsed time.Duration) float64 {
+	if elapsed > p.Duration {
+		elapsed = p.Duration
+	}
+
+	var (
+		t = elapsed.Seconds()
+		d = p.Duration.Seconds()
+
+		from = float64(p.From)
+		to   = float64(p.To)
+	)
+
+	// The integral of the linear rate function
+	return from*t + (to-from)*t*t/(2*d)
+}
+
+// Total returns the total number of transactions in the phase
+func (p Phase) Total() float64 {
+	return p.Due(p.Duration)
+}
+
+// Profile is the load profile of a run,
+// made up of consecutive phases
+type Profile []Phase
+
+// NewConstant creates a single-phase profile with a constant send rate
+func NewConstant(rate uint64, duration time.Duration) Profile {
+	return Profile{
+		{
+			Name:     fmt.Sprintf("%s-1", Constant),
+			Shape:    Constant,
+			From:     rate,
+			To:       rate,
+			Duration: duration,
+		},
+	}
+}
+
+// Duration returns the total duration of the profile
+func (p Profile) Duration() time.Duration {
+	var duration time.Duration
+
+	for _, phase := range p {
+		duration += phase.Duration
+	}
+
+	return duration
+}
+
+// Total returns the total number of transactions in the profile
+func (p Profile) Total() uint64 {
+	return uint64(p.Due(p.Duration()))
+}
+
+// Due returns the number of transactions that are due to be sent out,
+// the given time after the profile start
+func (p Profile) Due(elapsed time.Duration) float64 {
+	due := 0.0
+
+	for _, phase := range p {
+		due += phase.Due(elapsed)
+
+		if elapsed <= phase.Duration {
+			break
+		}
+
+		elapsed -= phase.Duration
+	}
+
+	return due
+}
+
+// PhaseAt returns the index of the phase that is active
+// the given time after the profile start
+func (p Profile) PhaseAt(elapsed time.Duration) int {
+	for index, phase := range p {
+		if elapsed < phase.Duration {
+			return index
+		}
+
+		elapsed -= phase.Duration
+	}
+
+	return len(p) - 1
+}
+
+// Parse parses the load profile specification.
+// The specification is a comma separated list of phases:
+//
+//   - constant:<rate>:<duration> holds the send rate
+//   - ramp:<from>:<to>:<duration> linearly changes the send rate
+//   - steps:<from>:<to>:<increment>:<duration> increases the send rate in steps, each held for the duration
+//   - spike:<rate>:<duration> sends out a sudden burst at the send rate
+//
+// For example: ramp:10:200:1m,spike:1000:5s,steps:50:200:50:30s
+func Parse(spec string) (Profile, error) {
+	profile := make(Profile, 0)
+
+	for _, phaseSpec := range strings.Split(spec, phaseSeparator) {
+		phases, err := parsePhase(strings.TrimSpace(phaseSpec))
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse phase %q, %w", phaseSpec, err)
+		}
+
+		profile = append(profile, phases...)
+	}
+
+	if len(profile) == 0 {
+		return nil, errEmptyProfile
+	}
+
+	// Name the phases based on their order
+	for index := range profile {
+		profile[index].Name = fmt.Sprintf("%s-%d", profile[index].Shape, index+1)
+	}
+
+	return profile, nil
+}
+
+// parsePhase parses a single phase specification,
+// which can expand into multiple phases
+func parsePhase(spec string) ([]Phase, error) {
+	params := strings.Split(spec, paramSeparator)
+	if len(params) < 3 {
+		return nil, errInvalidPhase
+	}
+
+	// The duration is always the last parameter
+	duration, err := time.ParseDuration(params[len(params)-1])
+	if err != nil || duration <= 0 {
+		return nil, errInvalidDuration
+	}
+
+	rates, err := parseRates(params[1 : len(params)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	switch shape := params[0]; {
+	case shape == string(Constant) && len(rates) == 1:
+		return []Phase{newPhase(Constant, rates[0], rates[0], duration)}, nil
+	case shape == string(Spike) && len(rates) == 1:
+		return []Phase{newPhase(Spike, rates[0], rates[0], duration)}, nil
+	case shape == string(Ramp) && len(rates) == 2:
+		return []Phase{newPhase(Ramp, rates[0], rates[1], duration)}, nil
+	case shape == steps && len(rates) == 3:
+		from, to, increment := rates[0], rates[1], rates[2]
+		if increment == 0 || from > to {
+			return nil, errInvalidStep
+		}
+
+		phases := make([]Phase, 0, (to-from)/increment+1)
+		for rate := from; rate <= to; rate += increment {
+			phases = append(phases, newPhase(Step, rate, rate, duration))
+		}
+
+		return phases, nil
+	case shape == string(Constant),
+		shape == string(Spike),
+		shape == string(Ramp),
+		shape == steps:
+		return nil, errInvalidPhase
+	default:
+		return nil, errInvalidShape
+	}
+}
+
+// parseRates parses the phase send rates
+func parseRates(params []string) ([]uint64, error) {
+	rates := make([]uint64, 0, len(params))
+
+	for _, param := range params {
+		rate, err := strconv.ParseUint(param, 10, 64)
+		if err != nil {
+			return nil, errInvalidRate
+		}
+
+		rates = append(rates, rate)
+	}
+
+	return rates, nil
+}
+
+// newPhase creates a new unnamed profile phase
+func newPhase(shape Shape, from, to uint64, duration time.Duration) Phase {
+	return Phase{
+		Shape:    shape,
+		From:     from,
+		To:       to,
+		Duration: duration,
+	}
+}
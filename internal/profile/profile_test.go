@@ -0,0 +1,107 @@
+package profile
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfile_Parse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid profile", func(t *testing.T) {
+		t.Parallel()
+
+		profile, err := Parse("ramp:10:200:1m, spike:1000:5s,steps:50:150:50:30s,constant:20:10s")
+		require.NoError(t, err)
+
+		expected := Profile{
+			{Name: "ramp-1", Shape: Ramp, From: 10, To: 200, Duration: time.Minute},
+			{Name: "spike-2", Shape: Spike, From: 1000, To: 1000, Duration: 5 * time.Second},
+			{Name: "step-3", Shape: Step, From: 50, To: 50, Duration: 30 * time.Second},
+			{Name: "step-4", Shape: Step, From: 100, To: 100, Duration: 30 * time.Second},
+			{Name: "step-5", Shape: Step, From: 150, To: 150, Duration: 30 * time.Second},
+			{Name: "constant-6", Shape: Constant, From: 20, To: 20, Duration: 10 * time.Second},
+		}
+
+		assert.Equal(t, expected, profile)
+		assert.Equal(t, 2*time.Minute+45*time.Second, profile.Duration())
+	})
+
+	t.Run("invalid profile", func(t *testing.T) {
+		t.Parallel()
+
+		testTable := []struct {
+			name        string
+			spec        string
+			expectedErr error
+		}{
+			{
+				"unknown shape",
+				"sine:10:1m",
+				errInvalidShape,
+			},
+			{
+				"missing parameters",
+				"ramp:10:1m",
+				errInvalidPhase,
+			},
+			{
+				"missing duration",
+				"constant",
+				errInvalidPhase,
+			},
+			{
+				"invalid duration",
+				"constant:10:abc",
+				errInvalidDuration,
+			},
+			{
+				"invalid rate",
+				"constant:-10:1m",
+				errInvalidRate,
+			},
+			{
+				"invalid step",
+				"steps:10:100:0:1m",
+				errInvalidStep,
+			},
+		}
+
+		for _, testCase := range testTable {
+			t.Run(testCase.name, func(t *testing.T) {
+				t.Parallel()
+
+				_, err := Parse(testCase.spec)
+				assert.ErrorIs(t, err, testCase.expectedErr)
+			})
+		}
+	})
+}
+
+func TestProfile_Due(t *testing.T) {
+	t.Parallel()
+
+	profile := Profile{
+		{Shape: Ramp, From: 0, To: 100, Duration: 10 * time.Second},
+		{Shape: Constant, From: 100, To: 100, Duration: 10 * time.Second},
+	}
+
+	// The ramp sends out half of its peak rate on average
+	assert.InDelta(t, 125.0, profile.Due(5*time.Second), 0.001)
+	assert.InDelta(t, 500.0, profile.Due(10*time.Second), 0.001)
+
+	// The constant phase follows the ramp
+	assert.InDelta(t, 1000.0, profile.Due(15*time.Second), 0.001)
+	assert.Equal(t, uint64(1500), profile.Total())
+
+	// The due count is capped at the profile end
+	assert.InDelta(t, 1500.0, profile.Due(time.Hour), 0.001)
+
+	// Make sure the active phase is valid
+	assert.Equal(t, 0, profile.PhaseAt(5*time.Second))
+	assert.Equal(t, 1, profile.PhaseAt(15*time.Second))
+	assert.Equal(t, 1, profile.PhaseAt(time.Hour))
+}
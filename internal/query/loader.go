@@ -0,0 +1,213 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	core_types "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/supernova/internal/collector"
+)
+
+// dataErrorType is the error type of the
+// queries whose data could not be generated
+const dataErrorType = "data error"
+
+// Loader drives concurrent ABCI query traffic
+// against the cluster endpoints, and measures the query latencies.
+// The query workers are spread across the endpoints in a round-robin fashion
+type Loader struct {
+	cfg     Config
+	targets []Target
+
+	requestTimeout time.Duration
+}
+
+// NewLoader creates a new instance of the query loader
+func NewLoader(cfg Config, targets ...Target) *Loader {
+	return &Loader{
+		cfg:            cfg,
+		targets:        targets,
+		requestTimeout: time.Second * 5,
+	}
+}
+
+// workerStats are the query stats of a single worker
+type workerStats struct {
+	latencies []time.Duration // the latencies of the succeeded queries
+	errors    map[string]int  // the number of errors per type
+	sent      int             // the number of sent queries
+}
+
+// Run runs the query load until the context is done,
+// and returns the query load result
+func (l *Loader) Run(ctx context.Context) *Result {
+	var (
+		start   = time.Now()
+		workers = make([]*workerStats, max(l.cfg.Concurrency, 1))
+		index   atomic.Int64
+
+		tokens <-chan struct{}
+		wg     sync.WaitGroup
+	)
+
+	// Rate-capped loads share the query tokens
+	if l.cfg.Rate > 0 {
+		tokens = rateTokens(ctx, l.cfg.Rate)
+	}
+
+	for worker := range workers {
+		stats := &workerStats{
+			latencies: make([]time.Duration, 0),
+			errors:    make(map[string]int),
+		}
+
+		workers[worker] = stats
+
+		wg.Add(1)
+
+		go func(target Target) {
+			defer wg.Done()
+
+			l.runWorker(ctx, target, tokens, &index, stats)
+		}(l.targets[worker%len(l.targets)])
+	}
+
+	wg.Wait()
+
+	return newResult(l.cfg.Path, workers, time.Since(start))
+}
+
+// runWorker sends out queries to the target,
+// one after another, until the context is done
+func (l *Loader) runWorker(
+	ctx context.Context,
+	target Target,
+	tokens <-chan struct{},
+	index *atomic.Int64,
+	stats *workerStats,
+) {
+	for {
+		if tokens != nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tokens:
+			}
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		data, err := l.cfg.DataFn(int(index.Add(1) - 1))
+		if err != nil {
+			// The query can't be sent out, so it's counted as failed
+			stats.sent++
+			stats.errors[dataErrorType]++
+
+			continue
+		}
+
+		queryCtx, cancelFn := context.WithTimeout(ctx, l.requestTimeout)
+
+		sentAt := time.Now()
+		res, err := target.Client.ExecuteABCIQuery(queryCtx, l.cfg.Path, data)
+		latency := time.Since(sentAt)
+
+		cancelFn()
+
+		// Queries interrupted by the end of the load are not counted
+		if ctx.Err() != nil {
+			return
+		}
+
+		stats.sent++
+
+		if errType := queryError(res, err); errType != "" {
+			stats.errors[errType]++
+
+			continue
+		}
+
+		stats.latencies = append(stats.latencies, latency)
+	}
+}
+
+// rateTokens emits query tokens at the given rate, until the context is done
+func rateTokens(ctx context.Context, rate uint64) <-chan struct{} {
+	tokens := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(time.Second / time.Duration(rate))
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			// Tokens are not accumulated if
+			// the workers can't keep up with the rate
+			select {
+			case tokens <- struct{}{}:
+			default:
+			}
+		}
+	}()
+
+	return tokens
+}
+
+// queryError returns the type name of the query error, if any,
+// which is used for grouping errors of the same kind
+func queryError(res *core_types.ResultABCIQuery, err error) string {
+	if err != nil {
+		return fmt.Sprintf("%T", err)
+	}
+
+	if res.Response.IsErr() {
+		return fmt.Sprintf("%T", res.Response.Error)
+	}
+
+	return ""
+}
+
+// newResult generates the query load result from the worker stats
+func newResult(path string, workers []*workerStats, duration time.Duration) *Result {
+	var (
+		latencies = make([]time.Duration, 0)
+		result    = &Result{
+			Path:   path,
+			Errors: make(map[string]int),
+		}
+	)
+
+	for _, stats := range workers {
+		latencies = append(latencies, stats.latencies...)
+
+		result.Sent += stats.sent
+
+		for errType, count := range stats.errors {
+			result.Errors[errType] += count
+			result.Failed += count
+		}
+	}
+
+	result.Succeeded = result.Sent - result.Failed
+	result.Latency = collector.CalculateLatency(latencies)
+
+	if result.Sent > 0 {
+		result.ErrorRate = float64(result.Failed) / float64(result.Sent) * 100
+	}
+
+	if duration > 0 {
+		result.QPS = float64(result.Sent) / duration.Seconds()
+	}
+
+	return result
+}
@@ -0,0 +1,179 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	core_types "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_Run(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mux     sync.Mutex
+		queried = make(map[string]int)
+		paths   = make(map[string]struct{})
+
+		client = &mockClient{
+			executeABCIQueryFn: func(_ context.Context, path string, data []byte) (*core_types.ResultABCIQuery, error) {
+				mux.Lock()
+				defer mux.Unlock()
+
+				paths[path] = struct{}{}
+				queried[string(data)]++
+
+				return &core_types.ResultABCIQuery{}, nil
+			},
+		}
+
+		cfg = Config{
+			Path: RenderPath,
+			DataFn: func(_ int) ([]byte, error) {
+				return []byte("gno.land/r/demo:"), nil
+			},
+			Concurrency: 4,
+		}
+	)
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancelFn()
+
+	result := NewLoader(cfg, Target{Client: client}).Run(ctx)
+
+	require.NotNil(t, result)
+
+	// Make sure the queries went out
+	assert.Positive(t, result.Sent)
+	assert.Equal(t, result.Sent, result.Succeeded)
+	assert.Zero(t, result.Failed)
+	assert.Zero(t, result.ErrorRate)
+	assert.Positive(t, result.QPS)
+	assert.Equal(t, RenderPath, result.Path)
+
+	require.NotNil(t, result.Latency)
+
+	// Make sure the query data and path are used
+	assert.Equal(t, map[string]struct{}{RenderPath: {}}, paths)
+	assert.Len(t, queried, 1)
+}
+
+func TestLoader_Errors(t *testing.T) {
+	t.Parallel()
+
+	var (
+		errTransport = errors.New("transport error")
+
+		mux   sync.Mutex
+		calls int
+
+		// Every other query fails, alternating
+		// between transport and query errors
+		client = &mockClient{
+			executeABCIQueryFn: func(_ context.Context, _ string, _ []byte) (*core_types.ResultABCIQuery, error) {
+				mux.Lock()
+				defer mux.Unlock()
+
+				calls++
+
+				switch calls % 4 {
+				case 1:
+					return nil, errTransport
+				case 3:
+					return &core_types.ResultABCIQuery{
+						Response: abci.ResponseQuery{
+							ResponseBase: abci.ResponseBase{
+								Error: abci.StringError("unknown realm"),
+							},
+						},
+					}, nil
+				default:
+					return &core_types.ResultABCIQuery{}, nil
+				}
+			},
+		}
+
+		cfg = Config{
+			Path: EvalPath,
+			DataFn: func(_ int) ([]byte, error) {
+				return []byte("gno.land/r/demo.Render(\"\")"), nil
+			},
+			Concurrency: 1,
+			Rate:        200,
+		}
+	)
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancelFn()
+
+	result := NewLoader(cfg, Target{Client: client}).Run(ctx)
+
+	require.NotNil(t, result)
+	require.Positive(t, result.Failed)
+
+	// Make sure the errors are grouped by type
+	assert.Equal(t, result.Sent, result.Succeeded+result.Failed)
+	assert.Contains(t, result.Errors, "*errors.errorString")
+	assert.Contains(t, result.Errors, "abci.StringError")
+	assert.InDelta(t, 50, result.ErrorRate, 20)
+
+	// Make sure the rate cap is respected
+	assert.LessOrEqual(t, result.Sent, 60)
+}
+
+func TestLoader_DataErrors(t *testing.T) {
+	t.Parallel()
+
+	var (
+		errData = errors.New("data error")
+
+		mux     sync.Mutex
+		queried = make(map[string]int)
+
+		client = &mockClient{
+			executeABCIQueryFn: func(_ context.Context, _ string, data []byte) (*core_types.ResultABCIQuery, error) {
+				mux.Lock()
+				defer mux.Unlock()
+
+				queried[string(data)]++
+
+				return &core_types.ResultABCIQuery{}, nil
+			},
+		}
+
+		// Every other query data can't be generated
+		cfg = Config{
+			Path: RenderPath,
+			DataFn: func(index int) ([]byte, error) {
+				if index%2 == 1 {
+					return nil, errData
+				}
+
+				return []byte("gno.land/r/demo:"), nil
+			},
+			Concurrency: 1,
+			Rate:        200,
+		}
+	)
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancelFn()
+
+	result := NewLoader(cfg, Target{Client: client}).Run(ctx)
+
+	require.NotNil(t, result)
+	require.Positive(t, result.Failed)
+
+	// Make sure the queries without data are not sent out,
+	// and are counted as failed
+	assert.Equal(t, result.Failed, result.Errors[dataErrorType])
+	assert.Positive(t, result.Succeeded)
+	assert.InDelta(t, result.Succeeded, queried["gno.land/r/demo:"], 1)
+	assert.Len(t, queried, 1)
+}
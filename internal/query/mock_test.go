@@ -0,0 +1,25 @@
+package query
+
+import (
+	"context"
+
+	core_types "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+)
+
+type executeABCIQueryDelegate func(context.Context, string, []byte) (*core_types.ResultABCIQuery, error)
+
+type mockClient struct {
+	executeABCIQueryFn executeABCIQueryDelegate
+}
+
+func (m *mockClient) ExecuteABCIQuery(
+	ctx context.Context,
+	path string,
+	data []byte,
+) (*core_types.ResultABCIQuery, error) {
+	if m.executeABCIQueryFn != nil {
+		return m.executeABCIQueryFn(ctx, path, data)
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,53 @@
+package query
+
+import (
+	"context"
+
+	core_types "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/supernova/internal/collector"
+)
+
+const (
+	RenderPath = "vm/qrender" // renders the realm (Render), <realm>:<path>
+	EvalPath   = "vm/qeval"   // evaluates an expression in the realm, <realm>.<expression>
+)
+
+type Client interface {
+	ExecuteABCIQuery(ctx context.Context, path string, data []byte) (*core_types.ResultABCIQuery, error)
+}
+
+// Target is a single endpoint the queries are sent to
+type Target struct {
+	Client   Client // the endpoint client
+	Endpoint string // the endpoint URL
+}
+
+// DataFn returns the data of the query with the given index
+type DataFn func(index int) ([]byte, error)
+
+// Config is the query load configuration
+type Config struct {
+	DataFn      DataFn // the query data callback
+	Path        string // the ABCI query path
+	Concurrency int    // the number of concurrent query workers
+	Rate        uint64 // the total query rate cap (queries / s), if any
+}
+
+// Result is the query load result
+type Result struct {
+	Latency   *collector.LatencyResult `json:"latency"`
+	Errors    map[string]int           `json:"errors"` // the number of errors per type
+	Path      string                   `json:"path"`
+	Sent      int                      `json:"sent"`
+	Succeeded int                      `json:"succeeded"`
+	Failed    int                      `json:"failed"`
+	ErrorRate float64                  `json:"errorRate"` // the share of failed queries (percentage)
+	QPS       float64                  `json:"qps"`
+	Partial   bool                     `json:"partial,omitempty"` // the load was interrupted before the run ended
+}
+
+// IsQueryPath checks if the passed in
+// ABCI query path is supported
+func IsQueryPath(path string) bool {
+	return path == RenderPath || path == EvalPath
+}
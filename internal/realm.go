@@ -0,0 +1,114 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/supernova/internal/runtime"
+)
+
+const (
+	gnomodFileName = "gnomod.toml"
+	templateSuffix = ".tmpl"
+)
+
+var errMissingRealmFiles = errors.New("no .gno files found")
+
+// loadRealm loads the custom realm at the given path
+func loadRealm(path string) (*runtime.PackageTemplate, error) {
+	files, err := readRealmFiles(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return runtime.ParsePackageTemplate(files)
+}
+
+// loadScript loads the custom script at the given path.
+// Scripts are read in the same way as realms
+func loadScript(path string) (*runtime.PackageTemplate, error) {
+	files, err := readRealmFiles(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return runtime.ParseScriptTemplate(files)
+}
+
+// readRealmFiles reads the custom realm package files at the given path.
+// The path is either a single .gno file, or a directory of .gno files (and an optional gnomod.toml).
+// Any of the files can be a template, with the .tmpl suffix. Test files in the directory are skipped
+func readRealmFiles(path string) ([]*std.MemFile, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to stat realm path, %w", err)
+	}
+
+	if !info.IsDir() {
+		body, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read realm file, %w", err)
+		}
+
+		return []*std.MemFile{
+			{
+				Name: filepath.Base(path),
+				Body: string(body),
+			},
+		}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read realm directory, %w", err)
+	}
+
+	var (
+		files    = make([]*std.MemFile, 0, len(entries))
+		hasFiles = false
+	)
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		if entry.IsDir() || !isRealmFile(name) {
+			continue
+		}
+
+		body, err := os.ReadFile(filepath.Join(path, name))
+		if err != nil {
+			return nil, fmt.Errorf("unable to read realm file, %w", err)
+		}
+
+		hasFiles = hasFiles || strings.TrimSuffix(name, templateSuffix) != gnomodFileName
+
+		files = append(files, &std.MemFile{
+			Name: name,
+			Body: string(body),
+		})
+	}
+
+	if !hasFiles {
+		return nil, fmt.Errorf("%w in %s", errMissingRealmFiles, path)
+	}
+
+	return files, nil
+}
+
+// isRealmFile checks if the file with the given
+// name is part of the deployed realm package
+func isRealmFile(name string) bool {
+	name = strings.TrimSuffix(name, templateSuffix)
+
+	if name == gnomodFileName {
+		return true
+	}
+
+	return strings.HasSuffix(name, ".gno") &&
+		!strings.HasSuffix(name, "_test.gno") &&
+		!strings.HasSuffix(name, "_filetest.gno")
+}
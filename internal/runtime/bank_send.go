@@ -0,0 +1,203 @@
+package runtime
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/supernova/internal/common"
+)
+
+// TransferPattern is the pattern in which
+// the bank transfers flow between sub-accounts
+type TransferPattern string
+
+const (
+	RingTransfer   TransferPattern = "ring"   // each account sends to the next one
+	RandomTransfer TransferPattern = "random" // each account sends to a random other account
+	FanInTransfer  TransferPattern = "fan-in" // all accounts send to a single hot account
+)
+
+// IsTransferPattern checks if the passed in
+// transfer pattern is supported
+func IsTransferPattern(pattern TransferPattern) bool {
+	return pattern == RingTransfer ||
+		pattern == RandomTransfer ||
+		pattern == FanInTransfer
+}
+
+// accountRuntime is a runtime whose messages
+// depend on the accounts participating in the run
+type accountRuntime interface {
+	setAccounts(accounts []std.Account)
+}
+
+type bankSend struct {
+	ctx       context.Context
+	pattern   TransferPattern
+	amount    int64
+	msgsPerTx int
+
+	recipients []crypto.Address
+	positions  map[crypto.Address]int // account address -> recipient index
+	rand       *rand.Rand
+}
+
+func newBankSend(ctx context.Context, pattern TransferPattern, amount int64, msgsPerTx int) *bankSend {
+	if pattern == "" {
+		pattern = RingTransfer
+	}
+
+	return &bankSend{
+		ctx:       ctx,
+		pattern:   pattern,
+		amount:    amount,
+		msgsPerTx: msgsPerTx,
+		positions: make(map[crypto.Address]int),
+		//nolint:gosec // The recipients don't need to be cryptographically random
+		rand: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (b *bankSend) Initialize(
+	_ std.Account,
+	_ SignFn,
+	_ EstimateGasFn,
+	_ int64,
+	_ std.GasPrice,
+) ([]*std.Tx, error) {
+	// No extra setup needed for this runtime type
+	return nil, nil
+}
+
+func (b *bankSend) CalculateRuntimeCosts(
+	account std.Account,
+	estimateFn EstimateGasFn,
+	signFn SignFn,
+	currentMaxGas int64,
+	gasPrice std.GasPrice,
+	transactions uint64,
+) (std.Coin, error) {
+	cost, err := calculateRuntimeCosts(
+		b.ctx,
+		account,
+		transactions,
+		currentMaxGas,
+		gasPrice,
+		b.getMsgFn,
+		b.msgsPerTx,
+		signFn,
+		estimateFn,
+	)
+	if err != nil {
+		return std.Coin{}, err
+	}
+
+	// Besides the fees, each account needs
+	// to cover the transferred amounts
+	cost.Amount += int64(transactions) * int64(b.msgsPerTx) * b.amount
+
+	return cost, nil
+}
+
+func (b *bankSend) ConstructTransactions(
+	keys []crypto.PrivKey,
+	accounts []std.Account,
+	transactions uint64,
+	maxGas int64,
+	gasPrice std.GasPrice,
+	chainID string,
+	estimateFn EstimateGasFn,
+) ([]*std.Tx, error) {
+	b.setAccounts(accounts)
+
+	return constructTransactions(
+		b.ctx,
+		keys,
+		accounts,
+		transactions,
+		maxGas,
+		gasPrice,
+		chainID,
+		b.getMsgFn,
+		b.msgsPerTx,
+		estimateFn,
+	)
+}
+
+func (b *bankSend) NewGenerator(
+	keys []crypto.PrivKey,
+	accounts []std.Account,
+	maxGas int64,
+	gasPrice std.GasPrice,
+	chainID string,
+	estimateFn EstimateGasFn,
+) (*Generator, error) {
+	b.setAccounts(accounts)
+
+	return newGenerator(
+		b.ctx,
+		keys,
+		accounts,
+		maxGas,
+		gasPrice,
+		chainID,
+		b.getMsgFn,
+		b.msgsPerTx,
+		estimateFn,
+	)
+}
+
+// setAccounts sets the accounts that
+// participate in the transfers
+func (b *bankSend) setAccounts(accounts []std.Account) {
+	b.recipients = make([]crypto.Address, 0, len(accounts))
+	b.positions = make(map[crypto.Address]int, len(accounts))
+
+	for index, account := range accounts {
+		b.recipients = append(b.recipients, account.GetAddress())
+		b.positions[account.GetAddress()] = index
+	}
+}
+
+// msgSources returns the message constructor of the runtime
+func (b *bankSend) msgSources() []weightedMsgFn {
+	return singleSource(b.getMsgFn)
+}
+
+func (b *bankSend) getMsgFn(creator std.Account, _ int) (std.Msg, error) {
+	return bank.MsgSend{
+		FromAddress: creator.GetAddress(),
+		ToAddress:   b.recipient(creator.GetAddress()),
+		Amount:      std.NewCoins(std.NewCoin(common.Denomination, b.amount)),
+	}, nil
+}
+
+// recipient returns the recipient of the sender's transfer,
+// based on the transfer pattern. Senders that are not part of the run
+// accounts (or runs with a single account) transfer to themselves
+func (b *bankSend) recipient(sender crypto.Address) crypto.Address {
+	position, ok := b.positions[sender]
+	if !ok || len(b.recipients) < 2 {
+		return sender
+	}
+
+	switch b.pattern {
+	case RandomTransfer:
+		// Pick any account other than the sender
+		index := b.rand.Intn(len(b.recipients) - 1)
+		if index >= position {
+			index++
+		}
+
+		return b.recipients[index]
+	case FanInTransfer:
+		// The first account is the hot account
+		return b.recipients[0]
+	default:
+		return b.recipients[(position+1)%len(b.recipients)]
+	}
+}
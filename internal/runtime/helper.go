@@ -2,6 +2,7 @@ package runtime
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/gnolang/gno/tm2/pkg/crypto"
@@ -13,33 +14,200 @@ import (
 
 const gasBuffer = 10_000 // 10k gas
 
+var errTxExceedsBlockGas = errors.New("transaction exceeds the block gas limit")
+
 // msgFn defines the transaction message constructor
-type msgFn func(creator std.Account, index int) std.Msg
+type msgFn func(creator std.Account, index int) (std.Msg, error)
+
+// txFn defines the whole transaction constructor (messages and fee),
+// used by runtimes whose transactions differ from one another
+type txFn func(creator std.Account, index int) ([]std.Msg, std.Fee, error)
+
+// weightedMsgFn is a transaction message constructor,
+// with its share in the generated transactions
+type weightedMsgFn struct {
+	getMsg      msgFn
+	estimateMsg msgFn // the constructor of the messages the gas is estimated with, if not getMsg
+	weight      uint64
+	headroom    int64 // the gas headroom (percentage) added to the estimated gas, if any
+}
 
-// constructTransactions constructs and signs the transactions
-// using the passed in message generator and signer
-func constructTransactions(
+// estimationMsg returns the constructor
+// of the messages the gas is estimated with
+func (w weightedMsgFn) estimationMsg() msgFn {
+	if w.estimateMsg != nil {
+		return w.estimateMsg
+	}
+
+	return w.getMsg
+}
+
+// withHeadroom adds the gas headroom to the estimated gas
+func (w weightedMsgFn) withHeadroom(gas int64) int64 {
+	return gas + gas*w.headroom/100
+}
+
+// singleSource returns the message constructor as the only weighted constructor
+func singleSource(getMsg msgFn) []weightedMsgFn {
+	return []weightedMsgFn{{getMsg: getMsg, weight: 1}}
+}
+
+// sourcesWeight returns the total weight of the message constructors
+func sourcesWeight(sources []weightedMsgFn) uint64 {
+	var total uint64
+
+	for _, source := range sources {
+		total += source.weight
+	}
+
+	return total
+}
+
+// msgSource is a weighted transaction message
+// constructor, with its estimated transaction fee
+type msgSource struct {
+	getMsg msgFn
+	fee    std.Fee
+
+	weight  int64
+	current int64 // the current weight, used for interleaving the sources
+}
+
+// Generator constructs and signs stress test
+// transactions on demand, one at a time
+type Generator struct {
+	sources []*msgSource
+	getTx   txFn // the transaction constructor, used instead of the sources if set
+
+	// A local nonce map is updated to avoid unnecessary calls
+	// for fetching the fresh info from the chain every time
+	// an account is used
+	nonceMap map[uint64]uint64 // accountNumber -> nonce
+
+	chainID     string
+	keys        []crypto.PrivKey
+	accounts    []std.Account
+	totalWeight int64
+	msgsPerTx   int
+	index       int
+}
+
+// newGenerator creates a new transaction generator, estimating
+// the transaction fee from the first generated message
+func newGenerator(
 	ctx context.Context,
 	keys []crypto.PrivKey,
 	accounts []std.Account,
-	transactions uint64,
 	maxGas int64,
 	gasPrice std.GasPrice,
 	chainID string,
 	getMsg msgFn,
+	msgsPerTx int,
 	estimateFn EstimateGasFn,
-) ([]*std.Tx, error) {
-	var (
-		txs = make([]*std.Tx, transactions)
-
-		// A local nonce map is updated to avoid unnecessary calls
-		// for fetching the fresh info from the chain every time
-		// an account is used
-		nonceMap = make(map[uint64]uint64) // accountNumber -> nonce
+) (*Generator, error) {
+	return newWeightedGenerator(
+		ctx,
+		keys,
+		accounts,
+		maxGas,
+		gasPrice,
+		chainID,
+		singleSource(getMsg),
+		msgsPerTx,
+		estimateFn,
 	)
+}
 
+// newWeightedGenerator creates a new transaction generator that interleaves
+// the messages of the given constructors, based on their weights.
+// Each transaction holds the given number of messages of a single constructor.
+// The transaction fee of each constructor is estimated from its first transaction
+func newWeightedGenerator(
+	ctx context.Context,
+	keys []crypto.PrivKey,
+	accounts []std.Account,
+	maxGas int64,
+	gasPrice std.GasPrice,
+	chainID string,
+	msgFns []weightedMsgFn,
+	msgsPerTx int,
+	estimateFn EstimateGasFn,
+) (*Generator, error) {
 	fmt.Printf("\n⏳ Estimating Gas ⏳\n")
 
+	var (
+		sources     = make([]*msgSource, 0, len(msgFns))
+		totalWeight int64
+	)
+
+	for _, msgFn := range msgFns {
+		fee, err := estimateFee(
+			ctx,
+			keys[0],
+			accounts[0],
+			maxGas,
+			gasPrice,
+			chainID,
+			msgFn,
+			msgsPerTx,
+			estimateFn,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		fmt.Printf("\nEstimated Gas for 1 run tx: %d \n", fee.GasWanted)
+
+		sources = append(sources, &msgSource{
+			getMsg: msgFn.getMsg,
+			fee:    fee,
+			weight: int64(msgFn.weight),
+		})
+
+		totalWeight += int64(msgFn.weight)
+	}
+
+	return &Generator{
+		sources:     sources,
+		nonceMap:    make(map[uint64]uint64),
+		chainID:     chainID,
+		keys:        keys,
+		accounts:    accounts,
+		totalWeight: totalWeight,
+		msgsPerTx:   msgsPerTx,
+	}, nil
+}
+
+// newTxGenerator creates a new transaction generator that constructs
+// each transaction (messages and fee) using the given constructor
+func newTxGenerator(
+	keys []crypto.PrivKey,
+	accounts []std.Account,
+	chainID string,
+	getTx txFn,
+) *Generator {
+	return &Generator{
+		getTx:    getTx,
+		nonceMap: make(map[uint64]uint64),
+		chainID:  chainID,
+		keys:     keys,
+		accounts: accounts,
+	}
+}
+
+// estimateFee estimates the transaction fee
+// using the first transaction of the constructor
+func estimateFee(
+	ctx context.Context,
+	creatorKey crypto.PrivKey,
+	creator std.Account,
+	maxGas int64,
+	gasPrice std.GasPrice,
+	chainID string,
+	source weightedMsgFn,
+	msgsPerTx int,
+	estimateFn EstimateGasFn,
+) (std.Fee, error) {
 	// Estimate the fee for the transaction batch
 	// passing in the maximum block gas, this is just a simulation
 	txFee := common.CalculateFeeInRatio(
@@ -48,13 +216,13 @@ func constructTransactions(
 	)
 
 	// Construct the first tx
-	var (
-		creator    = accounts[0]
-		creatorKey = keys[0]
-	)
+	msgs, err := txMsgs(source.estimationMsg(), creator, 0, msgsPerTx)
+	if err != nil {
+		return std.Fee{}, err
+	}
 
 	tx := &std.Tx{
-		Msgs: []std.Msg{getMsg(creator, 0)},
+		Msgs: msgs,
 		Fee:  txFee,
 	}
 
@@ -66,66 +234,171 @@ func constructTransactions(
 	}
 
 	if err := signer.SignTx(tx, creatorKey, cfg); err != nil {
-		return nil, fmt.Errorf("unable to sign transaction, %w", err)
+		return std.Fee{}, fmt.Errorf("unable to sign transaction, %w", err)
 	}
 
 	gasWanted, err := estimateFn(ctx, tx)
 	if err != nil {
-		return nil, fmt.Errorf("unable to estimate gas, %w", err)
+		return std.Fee{}, fmt.Errorf("unable to estimate gas, %w", err)
 	}
 
-	// Clear the old signatures, because they need
-	// to be regenerated
-	clear(tx.Signatures)
+	gasWanted = source.withHeadroom(gasWanted)
+
+	// Make sure the transaction fits into a block
+	if maxGas > 0 && gasWanted > maxGas {
+		return std.Fee{}, fmt.Errorf(
+			"%w, %d gas (%d msgs) exceeds the block gas limit %d",
+			errTxExceedsBlockGas,
+			gasWanted,
+			msgsPerTx,
+			maxGas,
+		)
+	}
 
 	// Use the estimated gas limit
-	txFee = common.CalculateFeeInRatio(gasWanted+gasBuffer, gasPrice) // 10k gas buffer
+	return common.CalculateFeeInRatio(gasWanted+gasBuffer, gasPrice), nil // 10k gas buffer
+}
+
+// Next generates and signs the next transaction in the sequence.
+// Transaction creators are rotated between the generator accounts
+func (g *Generator) Next() (*std.Tx, error) {
+	// Generate the transaction
+	var (
+		creator       = g.accounts[g.index%len(g.accounts)]
+		creatorKey    = g.keys[g.index%len(g.accounts)]
+		accountNumber = creator.GetAccountNumber()
+	)
+
+	var (
+		tx  = &std.Tx{}
+		err error
+	)
+
+	if g.getTx != nil {
+		tx.Msgs, tx.Fee, err = g.getTx(creator, g.index)
+	} else {
+		source := g.nextSource()
+
+		tx.Msgs, err = txMsgs(source.getMsg, creator, g.index, g.msgsPerTx)
+		tx.Fee = source.fee
+	}
 
-	if err = signer.SignTx(tx, creatorKey, cfg); err != nil {
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch the next account nonce
+	nonce, found := g.nonceMap[accountNumber]
+	if !found {
+		nonce = creator.GetSequence()
+		g.nonceMap[accountNumber] = nonce
+	}
+
+	// Sign the transaction
+	cfg := signer.SignCfg{
+		ChainID:       g.chainID,
+		AccountNumber: accountNumber,
+		Sequence:      nonce,
+	}
+
+	if err := signer.SignTx(tx, creatorKey, cfg); err != nil {
 		return nil, fmt.Errorf("unable to sign transaction, %w", err)
 	}
 
-	fmt.Printf("\nEstimated Gas for 1 run tx: %d \n", tx.Fee.GasWanted)
-	fmt.Printf("\n🔨 Constructing Transactions 🔨\n\n")
+	// Increase the creator nonce locally
+	g.nonceMap[accountNumber] = nonce + 1
+	g.index++
 
-	bar := progressbar.Default(int64(transactions), "constructing txs")
+	return tx, nil
+}
 
-	for i := 0; i < int(transactions); i++ {
-		// Generate the transaction
-		var (
-			creator       = accounts[i%len(accounts)]
-			creatorKey    = keys[i%len(accounts)]
-			accountNumber = creator.GetAccountNumber()
-		)
+// txMsgs constructs the messages of the transaction with the given index.
+// Messages are indexed across the run, so each one has a unique index
+func txMsgs(getMsg msgFn, creator std.Account, index, msgsPerTx int) ([]std.Msg, error) {
+	msgs := make([]std.Msg, 0, msgsPerTx)
 
-		tx := &std.Tx{
-			Msgs: []std.Msg{getMsg(creator, i)},
-			Fee:  txFee,
+	for i := 0; i < msgsPerTx; i++ {
+		msg, err := getMsg(creator, index*msgsPerTx+i)
+		if err != nil {
+			return nil, fmt.Errorf("unable to construct message %d, %w", index*msgsPerTx+i, err)
 		}
 
-		// Fetch the next account nonce
-		nonce, found := nonceMap[creator.GetAccountNumber()]
-		if !found {
-			nonce = creator.GetSequence()
-			nonceMap[creator.GetAccountNumber()] = nonce
-		}
+		msgs = append(msgs, msg)
+	}
 
-		// Sign the transaction
-		cfg := signer.SignCfg{
-			ChainID:       chainID,
-			AccountNumber: accountNumber,
-			Sequence:      nonce,
-		}
+	return msgs, nil
+}
+
+// nextSource picks the message source for the next transaction.
+// Sources are interleaved using a smooth weighted round-robin,
+// so each source is picked in proportion to its weight
+func (g *Generator) nextSource() *msgSource {
+	var picked *msgSource
+
+	for _, source := range g.sources {
+		source.current += source.weight
 
-		if err := signer.SignTx(tx, creatorKey, cfg); err != nil {
-			return nil, fmt.Errorf("unable to sign transaction, %w", err)
+		if picked == nil || source.current > picked.current {
+			picked = source
 		}
+	}
+
+	picked.current -= g.totalWeight
+
+	return picked
+}
+
+// constructTransactions constructs and signs the transactions
+// using the passed in message generator and signer
+func constructTransactions(
+	ctx context.Context,
+	keys []crypto.PrivKey,
+	accounts []std.Account,
+	transactions uint64,
+	maxGas int64,
+	gasPrice std.GasPrice,
+	chainID string,
+	getMsg msgFn,
+	msgsPerTx int,
+	estimateFn EstimateGasFn,
+) ([]*std.Tx, error) {
+	generator, err := newGenerator(
+		ctx,
+		keys,
+		accounts,
+		maxGas,
+		gasPrice,
+		chainID,
+		getMsg,
+		msgsPerTx,
+		estimateFn,
+	)
+	if err != nil {
+		return nil, err
+	}
 
-		// Increase the creator nonce locally
-		nonceMap[accountNumber] = nonce + 1
+	return generateTransactions(generator, transactions)
+}
+
+// generateTransactions generates the given
+// number of transactions using the generator
+func generateTransactions(generator *Generator, transactions uint64) ([]*std.Tx, error) {
+	fmt.Printf("\n🔨 Constructing Transactions 🔨\n\n")
+
+	var (
+		txs = make([]*std.Tx, transactions)
+		bar = progressbar.Default(int64(transactions), "constructing txs")
+	)
+
+	for i := 0; i < int(transactions); i++ {
+		tx, err := generator.Next()
+		if err != nil {
+			return nil, err
+		}
 
 		// Mark the transaction as ready
 		txs[i] = tx
+
 		_ = bar.Add(1) //nolint:errcheck // No need to check
 	}
 
@@ -134,6 +407,9 @@ func constructTransactions(
 	return txs, nil
 }
 
+// calculateRuntimeCosts calculates the fee budget of a single account,
+// based on the real fee of the given number of transactions the account sends out.
+// The fee is estimated using the first transaction of the constructor
 func calculateRuntimeCosts(
 	ctx context.Context,
 	account std.Account,
@@ -141,11 +417,83 @@ func calculateRuntimeCosts(
 	maxBlockMaxGas int64,
 	gasPrice std.GasPrice,
 	getMsg msgFn,
+	msgsPerTx int,
+	signFn SignFn,
+	estimateFn EstimateGasFn,
+) (std.Coin, error) {
+	return calculateWeightedRuntimeCosts(
+		ctx,
+		account,
+		transactions,
+		maxBlockMaxGas,
+		gasPrice,
+		singleSource(getMsg),
+		msgsPerTx,
+		signFn,
+		estimateFn,
+	)
+}
+
+// calculateWeightedRuntimeCosts calculates the fee budget of a single account, for the
+// transactions of the given constructors, interleaved based on their weights.
+// The fee of each constructor is estimated using its first transaction
+func calculateWeightedRuntimeCosts(
+	ctx context.Context,
+	account std.Account,
+	transactions uint64,
+	maxBlockMaxGas int64,
+	gasPrice std.GasPrice,
+	msgFns []weightedMsgFn,
+	msgsPerTx int,
 	signFn SignFn,
 	estimateFn EstimateGasFn,
 ) (std.Coin, error) {
 	fmt.Printf("\n⏳ Estimating Gas ⏳\n")
 
+	var (
+		totalWeight = sourcesWeight(msgFns)
+		cost        = std.Coin{
+			Denom:  common.Denomination,
+			Amount: 0,
+		}
+	)
+
+	for _, msgFn := range msgFns {
+		fee, err := estimateRuntimeFee(
+			ctx,
+			account,
+			maxBlockMaxGas,
+			gasPrice,
+			msgFn,
+			msgsPerTx,
+			signFn,
+			estimateFn,
+		)
+		if err != nil {
+			return std.Coin{}, err
+		}
+
+		// The constructor share of the transactions (rounded up)
+		sourceTxs := (transactions*msgFn.weight + totalWeight - 1) / totalWeight
+
+		cost.Amount += int64(sourceTxs) * fee.GasFee.Amount
+	}
+
+	return cost, nil
+}
+
+// estimateRuntimeFee estimates the fee of a single transaction
+// of the constructor, sent out by the given account
+func estimateRuntimeFee(
+	ctx context.Context,
+	account std.Account,
+	maxBlockMaxGas int64,
+	gasPrice std.GasPrice,
+	source weightedMsgFn,
+	msgsPerTx int,
+	signFn SignFn,
+	estimateFn EstimateGasFn,
+) (std.Fee, error) {
 	// Estimate the fee for the transaction batch
 	// passing in the maximum block gas, this is just a simulation
 	txFee := common.CalculateFeeInRatio(
@@ -153,36 +501,42 @@ func calculateRuntimeCosts(
 		gasPrice,
 	)
 
+	msgs, err := txMsgs(source.estimationMsg(), account, 0, msgsPerTx)
+	if err != nil {
+		return std.Fee{}, err
+	}
+
 	tx := &std.Tx{
-		Msgs: []std.Msg{getMsg(account, 0)},
+		Msgs: msgs,
 		Fee:  txFee,
 	}
 
-	err := signFn(tx)
+	err = signFn(tx)
 	if err != nil {
-		return std.Coin{}, fmt.Errorf("unable to sign transaction, %w", err)
+		return std.Fee{}, fmt.Errorf("unable to sign transaction, %w", err)
 	}
 
 	estimatedGas, err := estimateFn(ctx, tx)
 	if err != nil {
-		return std.Coin{}, fmt.Errorf("unable to estimate gas, %w", err)
+		return std.Fee{}, fmt.Errorf("unable to estimate gas, %w", err)
 	}
 
-	return std.Coin{
-		Denom:  common.Denomination,
-		Amount: int64(transactions) * estimatedGas,
-	}, nil
+	// Each transaction pays the fee for its estimated gas (with the buffer)
+	return common.CalculateFeeInRatio(source.withHeadroom(estimatedGas)+gasBuffer, gasPrice), nil
 }
 
+// SignTransactionsCb returns the signing callback for the given account.
+// The account sequence is read when signing, so runtimes that prepare
+// several transactions can advance it in between
 func SignTransactionsCb(chainID string, account std.Account, key crypto.PrivKey) SignFn {
-	// Sign the transaction
-	cfg := signer.SignCfg{
-		ChainID:       chainID,
-		AccountNumber: account.GetAccountNumber(),
-		Sequence:      account.GetSequence(),
-	}
-
 	return func(tx *std.Tx) error {
+		// Sign the transaction
+		cfg := signer.SignCfg{
+			ChainID:       chainID,
+			AccountNumber: account.GetAccountNumber(),
+			Sequence:      account.GetSequence(),
+		}
+
 		return signer.SignTx(tx, key, cfg)
 	}
 }
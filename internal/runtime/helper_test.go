@@ -2,6 +2,8 @@ package runtime
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/gnolang/gno/gno.land/pkg/gnoland"
@@ -46,8 +48,8 @@ func TestHelper_ConstructTransactions(t *testing.T) {
 		transactions = uint64(100)
 		msg          = vm.MsgAddPackage{}
 
-		getMsgFn = func(_ std.Account, _ int) std.Msg {
-			return msg
+		getMsgFn = func(_ std.Account, _ int) (std.Msg, error) {
+			return msg, nil
 		}
 	)
 
@@ -60,6 +62,7 @@ func TestHelper_ConstructTransactions(t *testing.T) {
 		common.DefaultGasPrice,
 		"dummy",
 		getMsgFn,
+		1,
 		func(_ context.Context, _ *std.Tx) (int64, error) {
 			return 1_000_000, nil
 		},
@@ -86,3 +89,188 @@ func TestHelper_ConstructTransactions(t *testing.T) {
 		assert.NotEmpty(t, tx.Msgs[0].GetSigners())
 	}
 }
+
+func TestHelper_Generator(t *testing.T) {
+	t.Parallel()
+
+	var (
+		numAccounts = 5
+		numTxs      = 20
+
+		accounts    = generateAccounts(numAccounts)
+		accountKeys = testutils.GenerateAccounts(t, numAccounts)
+
+		getMsgFn = func(creator std.Account, index int) (std.Msg, error) {
+			return vm.MsgCall{
+				Caller: creator.GetAddress(),
+				Args:   []string{fmt.Sprintf("%d", index)},
+			}, nil
+		}
+	)
+
+	generator, err := newGenerator(
+		context.Background(),
+		accountKeys,
+		accounts,
+		1_000_000,
+		common.DefaultGasPrice,
+		"dummy",
+		getMsgFn,
+		1,
+		func(_ context.Context, _ *std.Tx) (int64, error) {
+			return 1_000_000, nil
+		},
+	)
+	require.NoError(t, err)
+
+	for i := 0; i < numTxs; i++ {
+		tx, err := generator.Next()
+		require.NoError(t, err)
+
+		// Make sure the fee is valid
+		assert.Equal(
+			t,
+			common.CalculateFeeInRatio(1_000_000+gasBuffer, common.DefaultGasPrice),
+			tx.Fee,
+		)
+
+		// Make sure the creators are rotated
+		require.Len(t, tx.Signatures, 1)
+		assert.Equal(t, accountKeys[i%numAccounts].PubKey(), tx.Signatures[0].PubKey)
+
+		// Make sure the message index is valid
+		require.Len(t, tx.Msgs, 1)
+		assert.Equal(t, fmt.Sprintf("%d", i), tx.Msgs[0].(vm.MsgCall).Args[0])
+	}
+
+	// Make sure the local nonces were incremented
+	for _, account := range accounts {
+		assert.Equal(
+			t,
+			uint64(numTxs/numAccounts),
+			generator.nonceMap[account.GetAccountNumber()],
+		)
+	}
+}
+
+func TestHelper_GeneratorMsgError(t *testing.T) {
+	t.Parallel()
+
+	var (
+		errMsg = errors.New("msg error")
+
+		accounts    = generateAccounts(2)
+		accountKeys = testutils.GenerateAccounts(t, 2)
+
+		// Only the first message can be constructed
+		getMsgFn = func(creator std.Account, index int) (std.Msg, error) {
+			if index > 0 {
+				return nil, errMsg
+			}
+
+			return vm.MsgCall{
+				Caller: creator.GetAddress(),
+			}, nil
+		}
+	)
+
+	generator, err := newGenerator(
+		context.Background(),
+		accountKeys,
+		accounts,
+		1_000_000,
+		common.DefaultGasPrice,
+		"dummy",
+		getMsgFn,
+		1,
+		func(_ context.Context, _ *std.Tx) (int64, error) {
+			return 1_000_000, nil
+		},
+	)
+	require.NoError(t, err)
+
+	_, err = generator.Next()
+	require.NoError(t, err)
+
+	// Make sure the message error is not dropped
+	tx, err := generator.Next()
+	assert.ErrorIs(t, err, errMsg)
+	assert.Nil(t, tx)
+}
+
+func TestHelper_MultiMsgTransactions(t *testing.T) {
+	t.Parallel()
+
+	var (
+		transactions = uint64(10)
+		msgsPerTx    = 5
+
+		accounts    = generateAccounts(5)
+		accountKeys = testutils.GenerateAccounts(t, 5)
+
+		getMsgFn = func(creator std.Account, index int) (std.Msg, error) {
+			return vm.MsgCall{
+				Caller: creator.GetAddress(),
+				Args:   []string{fmt.Sprintf("%d", index)},
+			}, nil
+		}
+	)
+
+	t.Run("messages are indexed across the run", func(t *testing.T) {
+		t.Parallel()
+
+		txs, err := constructTransactions(
+			context.Background(),
+			accountKeys,
+			accounts,
+			transactions,
+			1_000_000,
+			common.DefaultGasPrice,
+			"dummy",
+			getMsgFn,
+			msgsPerTx,
+			func(_ context.Context, tx *std.Tx) (int64, error) {
+				// Make sure the estimated tx holds all messages
+				assert.Len(t, tx.Msgs, msgsPerTx)
+
+				return 100_000, nil
+			},
+		)
+		require.NoError(t, err)
+
+		require.Len(t, txs, int(transactions))
+
+		for txIndex, tx := range txs {
+			require.Len(t, tx.Msgs, msgsPerTx)
+
+			for msgIndex, msg := range tx.Msgs {
+				assert.Equal(
+					t,
+					fmt.Sprintf("%d", txIndex*msgsPerTx+msgIndex),
+					msg.(vm.MsgCall).Args[0],
+				)
+			}
+		}
+	})
+
+	t.Run("transaction exceeds the block gas limit", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := constructTransactions(
+			context.Background(),
+			accountKeys,
+			accounts,
+			transactions,
+			1_000_000,
+			common.DefaultGasPrice,
+			"dummy",
+			getMsgFn,
+			msgsPerTx,
+			func(_ context.Context, tx *std.Tx) (int64, error) {
+				return int64(len(tx.Msgs)) * 300_000, nil
+			},
+		)
+
+		assert.ErrorIs(t, err, errTxExceedsBlockGas)
+	})
+}
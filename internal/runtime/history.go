@@ -0,0 +1,297 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/supernova/internal/common"
+)
+
+// ErrEmptyHistory is returned when the history has no replayable transactions
+var ErrEmptyHistory = errors.New("no replayable transactions in the history")
+
+// HistoryTx is a committed transaction of the source chain
+type HistoryTx struct {
+	Tx     *std.Tx
+	Offset time.Duration // the commit time of the transaction, relative to the start of the history
+}
+
+// TxHistory is the source chain transaction history replayed by the history runtime.
+// Only the messages that can be re-created for the sub-accounts are replayed
+type TxHistory struct {
+	txs     []HistoryTx // the replayable transactions, in commit order
+	skipped int         // the number of source messages that can't be replayed
+}
+
+// NewTxHistory creates a new replayable history from the source transactions, in commit order.
+// Messages that can't be replayed are dropped, as are the transactions left without messages.
+// The offsets are shifted so the first replayable transaction is sent out right away
+func NewTxHistory(txs []HistoryTx) *TxHistory {
+	h := &TxHistory{
+		txs: make([]HistoryTx, 0, len(txs)),
+	}
+
+	for _, historyTx := range txs {
+		msgs := make([]std.Msg, 0, len(historyTx.Tx.Msgs))
+
+		for _, msg := range historyTx.Tx.Msgs {
+			if !isReplayable(msg) {
+				h.skipped++
+
+				continue
+			}
+
+			msgs = append(msgs, msg)
+		}
+
+		if len(msgs) == 0 {
+			continue
+		}
+
+		h.txs = append(h.txs, HistoryTx{
+			Tx: &std.Tx{
+				Msgs: msgs,
+				Fee:  historyTx.Tx.Fee,
+			},
+			Offset: historyTx.Offset,
+		})
+	}
+
+	if len(h.txs) > 0 {
+		start := h.txs[0].Offset
+
+		for index := range h.txs {
+			h.txs[index].Offset -= start
+		}
+	}
+
+	return h
+}
+
+// Len returns the number of replayable transactions
+func (h *TxHistory) Len() int {
+	return len(h.txs)
+}
+
+// Skipped returns the number of source messages that can't be replayed
+func (h *TxHistory) Skipped() int {
+	return h.skipped
+}
+
+// Schedule returns the send offset of each replayed transaction, keeping the
+// relative source timing, compressed by the speed factor (ex. 2 replays twice as fast)
+func (h *TxHistory) Schedule(speed float64) []time.Duration {
+	schedule := make([]time.Duration, 0, len(h.txs))
+
+	for _, historyTx := range h.txs {
+		schedule = append(schedule, time.Duration(float64(historyTx.Offset)/speed))
+	}
+
+	return schedule
+}
+
+// isReplayable checks if the message
+// can be re-created for a sub-account
+func isReplayable(msg std.Msg) bool {
+	switch msg := msg.(type) {
+	case bank.MsgSend, vm.MsgCall:
+		return true
+	case vm.MsgRun:
+		return msg.Package != nil
+	case vm.MsgAddPackage:
+		return msg.Package != nil
+	default:
+		return false
+	}
+}
+
+type historyReplay struct {
+	history *TxHistory
+	ctx     context.Context
+}
+
+func newHistoryReplay(ctx context.Context, history *TxHistory) *historyReplay {
+	return &historyReplay{
+		history: history,
+		ctx:     ctx,
+	}
+}
+
+func (h *historyReplay) Initialize(
+	_ std.Account,
+	_ SignFn,
+	_ EstimateGasFn,
+	_ int64,
+	_ std.GasPrice,
+) ([]*std.Tx, error) {
+	// No extra setup needed for this runtime type
+	return nil, nil
+}
+
+func (h *historyReplay) CalculateRuntimeCosts(
+	_ std.Account,
+	_ EstimateGasFn,
+	_ SignFn,
+	currentMaxGas int64,
+	gasPrice std.GasPrice,
+	transactions uint64,
+) (std.Coin, error) {
+	cost := std.Coin{
+		Denom: common.Denomination,
+	}
+
+	if len(h.history.txs) == 0 {
+		return cost, nil
+	}
+
+	// The source gas is reused, so nothing is estimated.
+	// The sub-accounts replay the history in turns,
+	// so each one is budgeted for the average source transaction
+	var total int64
+
+	for _, historyTx := range h.history.txs {
+		fee := replayFee(historyTx.Tx.Fee, currentMaxGas, gasPrice)
+
+		total += fee.GasFee.Amount + txValue(historyTx.Tx)
+	}
+
+	average := (total + int64(len(h.history.txs)) - 1) / int64(len(h.history.txs))
+	cost.Amount = int64(transactions) * average
+
+	return cost, nil
+}
+
+func (h *historyReplay) ConstructTransactions(
+	keys []crypto.PrivKey,
+	accounts []std.Account,
+	transactions uint64,
+	maxGas int64,
+	gasPrice std.GasPrice,
+	chainID string,
+	estimateFn EstimateGasFn,
+) ([]*std.Tx, error) {
+	generator, err := h.NewGenerator(keys, accounts, maxGas, gasPrice, chainID, estimateFn)
+	if err != nil {
+		return nil, err
+	}
+
+	return generateTransactions(generator, transactions)
+}
+
+func (h *historyReplay) NewGenerator(
+	keys []crypto.PrivKey,
+	accounts []std.Account,
+	maxGas int64,
+	gasPrice std.GasPrice,
+	chainID string,
+	_ EstimateGasFn,
+) (*Generator, error) {
+	if len(h.history.txs) == 0 {
+		return nil, ErrEmptyHistory
+	}
+
+	// The history is replayed in order, and starts over if it runs out
+	getTx := func(creator std.Account, index int) ([]std.Msg, std.Fee, error) {
+		source := h.history.txs[index%len(h.history.txs)].Tx
+
+		msgs := make([]std.Msg, 0, len(source.Msgs))
+
+		for position, msg := range source.Msgs {
+			msgs = append(msgs, replayMsg(msg, creator, index, position))
+		}
+
+		return msgs, replayFee(source.Fee, maxGas, gasPrice), nil
+	}
+
+	return newTxGenerator(keys, accounts, chainID, getTx), nil
+}
+
+// replayMsg re-creates the source message for the creator.
+// Packages are redeployed under the creator namespace, since the
+// source package paths are taken (or not owned by the creator)
+func replayMsg(msg std.Msg, creator std.Account, index, position int) std.Msg {
+	address := creator.GetAddress()
+
+	switch msg := msg.(type) {
+	case bank.MsgSend:
+		msg.FromAddress = address
+
+		return msg
+	case vm.MsgCall:
+		msg.Caller = address
+
+		return msg
+	case vm.MsgRun:
+		msg.Caller = address
+		msg.Package = &std.MemPackage{
+			Name: msg.Package.Name,
+			// The path is set by the VM, to the caller's run path
+			Path:  "",
+			Files: msg.Package.Files,
+		}
+
+		return msg
+	case vm.MsgAddPackage:
+		prefix := realmPathPrefix
+		if strings.HasPrefix(msg.Package.Path, packagePathPrefix) {
+			prefix = packagePathPrefix
+		}
+
+		msg.Creator = address
+		msg.Package = &std.MemPackage{
+			Name: msg.Package.Name,
+			Path: fmt.Sprintf(
+				"%s/%s/history_%d_%d_%d",
+				prefix,
+				address.String(),
+				time.Now().Unix(),
+				index,
+				position,
+			),
+			Files: msg.Package.Files,
+		}
+
+		return msg
+	default:
+		return msg
+	}
+}
+
+// replayFee returns the fee of the replayed transaction, for the source gas
+// (capped at the block gas limit), priced at the target network gas price
+func replayFee(source std.Fee, maxGas int64, gasPrice std.GasPrice) std.Fee {
+	gas := source.GasWanted
+	if maxGas > 0 {
+		gas = min(gas, maxGas)
+	}
+
+	return common.CalculateFeeInRatio(gas, gasPrice)
+}
+
+// txValue returns the value (in ugnot) the transaction
+// transfers, or might deposit for storage
+func txValue(tx *std.Tx) int64 {
+	var value int64
+
+	for _, msg := range tx.Msgs {
+		switch msg := msg.(type) {
+		case bank.MsgSend:
+			value += msg.Amount.AmountOf(common.Denomination)
+		case vm.MsgCall:
+			value += msg.Send.AmountOf(common.Denomination) + msg.MaxDeposit.AmountOf(common.Denomination)
+		case vm.MsgRun:
+			value += msg.Send.AmountOf(common.Denomination) + msg.MaxDeposit.AmountOf(common.Denomination)
+		case vm.MsgAddPackage:
+			value += msg.Send.AmountOf(common.Denomination) + msg.MaxDeposit.AmountOf(common.Denomination)
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,276 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/supernova/internal/common"
+)
+
+var (
+	errEmptyMix         = errors.New("empty mix specified")
+	errInvalidMixEntry  = errors.New("invalid mix entry")
+	errInvalidMixType   = errors.New("invalid mix runtime type")
+	errInvalidMixWeight = errors.New("invalid mix weight")
+	errDuplicateMixType = errors.New("duplicate mix runtime type")
+)
+
+// Weight is the share of a single
+// runtime in the mixed workload
+type Weight struct {
+	Type   Type   // the runtime type
+	Weight uint64 // the relative weight of the runtime
+}
+
+// ParseMix parses the mixed workload specification, which is
+// a comma separated list of <runtime>:<weight> entries (ex. REALM_CALL:70,PACKAGE_DEPLOYMENT:30)
+func ParseMix(spec string) ([]Weight, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, errEmptyMix
+	}
+
+	var (
+		entries = strings.Split(spec, ",")
+		weights = make([]Weight, 0, len(entries))
+		seen    = make(map[Type]struct{}, len(entries))
+	)
+
+	for _, entry := range entries {
+		params := strings.Split(strings.TrimSpace(entry), ":")
+		if len(params) != 2 {
+			return nil, fmt.Errorf("%w, %q", errInvalidMixEntry, entry)
+		}
+
+		runtimeType := Type(params[0])
+		if !IsRuntime(runtimeType) || runtimeType == Mixed || runtimeType == History {
+			return nil, fmt.Errorf("%w, %q", errInvalidMixType, params[0])
+		}
+
+		if _, ok := seen[runtimeType]; ok {
+			return nil, fmt.Errorf("%w, %q", errDuplicateMixType, params[0])
+		}
+
+		weight, err := strconv.ParseUint(params[1], 10, 64)
+		if err != nil || weight == 0 {
+			return nil, fmt.Errorf("%w, %q", errInvalidMixWeight, params[1])
+		}
+
+		seen[runtimeType] = struct{}{}
+
+		weights = append(weights, Weight{
+			Type:   runtimeType,
+			Weight: weight,
+		})
+	}
+
+	return weights, nil
+}
+
+// msgRuntime is a runtime that constructs
+// stress test transaction messages
+type msgRuntime interface {
+	Runtime
+
+	msgSources() []weightedMsgFn
+}
+
+// mixed is the runtime that interleaves
+// the messages of several runtimes, based on their weights
+type mixed struct {
+	ctx       context.Context
+	weights   []Weight
+	runtimes  []msgRuntime
+	msgsPerTx int
+}
+
+func newMixed(ctx context.Context, cfg Config) *mixed {
+	runtimes := make([]msgRuntime, 0, len(cfg.Mix))
+
+	for _, weight := range cfg.Mix {
+		//nolint:errcheck // All mix runtimes construct messages
+		runtimes = append(runtimes, GetRuntime(ctx, weight.Type, cfg).(msgRuntime))
+	}
+
+	return &mixed{
+		ctx:       ctx,
+		weights:   cfg.Mix,
+		runtimes:  runtimes,
+		msgsPerTx: cfg.msgsPerTx(),
+	}
+}
+
+func (m *mixed) Initialize(
+	account std.Account,
+	signFn SignFn,
+	estimateFn EstimateGasFn,
+	currentMaxGas int64,
+	gasPrice std.GasPrice,
+) ([]*std.Tx, error) {
+	txs := make([]*std.Tx, 0)
+
+	for _, r := range m.runtimes {
+		runtimeTxs, err := r.Initialize(account, signFn, estimateFn, currentMaxGas, gasPrice)
+		if err != nil {
+			return nil, err
+		}
+
+		txs = append(txs, runtimeTxs...)
+
+		// The transactions of the next runtime follow
+		// the prepared ones, so the account sequence is advanced
+		if err := account.SetSequence(account.GetSequence() + uint64(len(runtimeTxs))); err != nil {
+			return nil, fmt.Errorf("unable to advance account sequence, %w", err)
+		}
+	}
+
+	return txs, nil
+}
+
+func (m *mixed) deployedRealm() string {
+	// The first realm in the mix is used
+	for _, r := range m.runtimes {
+		if realm := DeployedRealm(r); realm != "" {
+			return realm
+		}
+	}
+
+	return ""
+}
+
+func (m *mixed) CalculateRuntimeCosts(
+	account std.Account,
+	estimateFn EstimateGasFn,
+	signFn SignFn,
+	currentMaxGas int64,
+	gasPrice std.GasPrice,
+	transactions uint64,
+) (std.Coin, error) {
+	total := std.Coin{
+		Denom:  common.Denomination,
+		Amount: 0,
+	}
+
+	for index, r := range m.runtimes {
+		cost, err := r.CalculateRuntimeCosts(
+			account,
+			estimateFn,
+			signFn,
+			currentMaxGas,
+			gasPrice,
+			m.share(index, transactions),
+		)
+		if err != nil {
+			return std.Coin{}, err
+		}
+
+		total.Amount += cost.Amount
+	}
+
+	return total, nil
+}
+
+// share returns the number of transactions the
+// runtime at the given index accounts for (rounded up)
+func (m *mixed) share(index int, transactions uint64) uint64 {
+	var totalWeight uint64
+
+	for _, weight := range m.weights {
+		totalWeight += weight.Weight
+	}
+
+	weight := m.weights[index].Weight
+
+	return (transactions*weight + totalWeight - 1) / totalWeight
+}
+
+func (m *mixed) ConstructTransactions(
+	keys []crypto.PrivKey,
+	accounts []std.Account,
+	transactions uint64,
+	maxGas int64,
+	gasPrice std.GasPrice,
+	chainID string,
+	estimateFn EstimateGasFn,
+) ([]*std.Tx, error) {
+	generator, err := m.NewGenerator(
+		keys,
+		accounts,
+		maxGas,
+		gasPrice,
+		chainID,
+		estimateFn,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return generateTransactions(generator, transactions)
+}
+
+func (m *mixed) NewGenerator(
+	keys []crypto.PrivKey,
+	accounts []std.Account,
+	maxGas int64,
+	gasPrice std.GasPrice,
+	chainID string,
+	estimateFn EstimateGasFn,
+) (*Generator, error) {
+	var (
+		sources = make([][]weightedMsgFn, 0, len(m.runtimes))
+		scale   = uint64(1)
+	)
+
+	for _, r := range m.runtimes {
+		// Prepare the runtimes that depend on the run accounts
+		if withAccounts, ok := r.(accountRuntime); ok {
+			withAccounts.setAccounts(accounts)
+		}
+
+		runtimeSources := r.msgSources()
+
+		sources = append(sources, runtimeSources)
+		scale = lcm(scale, sourcesWeight(runtimeSources))
+	}
+
+	// Runtimes can have several message constructors (ex. payload sweeps),
+	// whose weights are scaled so the runtime keeps its share of the mix
+	msgFns := make([]weightedMsgFn, 0, len(sources))
+
+	for index, runtimeSources := range sources {
+		factor := m.weights[index].Weight * scale / sourcesWeight(runtimeSources)
+
+		for _, source := range runtimeSources {
+			msgFns = append(msgFns, weightedMsgFn{
+				getMsg: source.getMsg,
+				weight: source.weight * factor,
+			})
+		}
+	}
+
+	return newWeightedGenerator(
+		m.ctx,
+		keys,
+		accounts,
+		maxGas,
+		gasPrice,
+		chainID,
+		msgFns,
+		m.msgsPerTx,
+		estimateFn,
+	)
+}
+
+// lcm returns the least common multiple of the given values
+func lcm(a, b uint64) uint64 {
+	x, y := a, b
+	for y != 0 {
+		x, y = y, x%y
+	}
+
+	return a / x * b
+}
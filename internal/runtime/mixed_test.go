@@ -0,0 +1,234 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/supernova/internal/common"
+	testutils "github.com/gnolang/supernova/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMixed_ParseMix(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name        string
+		spec        string
+		expected    []Weight
+		expectedErr error
+	}{
+		{
+			"valid mix",
+			"REALM_CALL:70, PACKAGE_DEPLOYMENT:30",
+			[]Weight{
+				{Type: RealmCall, Weight: 70},
+				{Type: PackageDeployment, Weight: 30},
+			},
+			nil,
+		},
+		{
+			"empty mix",
+			"",
+			nil,
+			errEmptyMix,
+		},
+		{
+			"missing weight",
+			"REALM_CALL",
+			nil,
+			errInvalidMixEntry,
+		},
+		{
+			"unknown runtime",
+			"DUMMY:10",
+			nil,
+			errInvalidMixType,
+		},
+		{
+			"nested mix",
+			"MIXED:10",
+			nil,
+			errInvalidMixType,
+		},
+		{
+			"duplicate runtime",
+			"REALM_CALL:10,REALM_CALL:20",
+			nil,
+			errDuplicateMixType,
+		},
+		{
+			"zero weight",
+			"REALM_CALL:0",
+			nil,
+			errInvalidMixWeight,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			weights, err := ParseMix(testCase.spec)
+
+			assert.ErrorIs(t, err, testCase.expectedErr)
+			assert.Equal(t, testCase.expected, weights)
+		})
+	}
+}
+
+func TestMixed_Runtime(t *testing.T) {
+	t.Parallel()
+
+	var (
+		transactions = uint64(100)
+		accounts     = generateAccounts(11)
+		accountKeys  = testutils.GenerateAccounts(t, 11)
+
+		estimateFn = func(_ context.Context, _ *std.Tx) (int64, error) {
+			return 1_000_000, nil
+		}
+	)
+
+	// Get the runtime
+	r := GetRuntime(context.Background(), Mixed, Config{
+		Mix: []Weight{
+			{Type: RealmCall, Weight: 3},
+			{Type: PackageDeployment, Weight: 1},
+		},
+	})
+
+	// Make sure the realm call initialization is present
+	initialTxs, err := r.Initialize(
+		accounts[0],
+		func(_ *std.Tx) error {
+			return nil
+		},
+		estimateFn,
+		1_000_000,
+		common.DefaultGasPrice,
+	)
+	require.NoError(t, err)
+
+	require.Len(t, initialTxs, 1)
+	assert.Equal(t, RealmDeployment, TxType(initialTxs[0]))
+
+	// Make sure the costs cover both runtimes
+	cost, err := r.CalculateRuntimeCosts(
+		accounts[0],
+		estimateFn,
+		func(_ *std.Tx) error {
+			return nil
+		},
+		1_000_000,
+		common.DefaultGasPrice,
+		transactions,
+	)
+	require.NoError(t, err)
+
+	txFee := common.CalculateFeeInRatio(1_000_000+gasBuffer, common.DefaultGasPrice).GasFee.Amount
+
+	assert.Equal(t, int64(transactions)*txFee, cost.Amount)
+
+	// Construct the transactions
+	txs, err := r.ConstructTransactions(
+		accountKeys[1:],
+		accounts[1:],
+		transactions,
+		1_000_000,
+		common.DefaultGasPrice,
+		"dummy",
+		estimateFn,
+	)
+	require.NoError(t, err)
+
+	require.Len(t, txs, int(transactions))
+
+	// Make sure the runtimes are interleaved based on their weights
+	counts := make(map[Type]int)
+
+	for index, tx := range txs {
+		txType := TxType(tx)
+		counts[txType]++
+
+		// Every fourth transaction is a package deployment
+		if index%4 == 2 {
+			assert.Equal(t, PackageDeployment, txType)
+
+			continue
+		}
+
+		assert.Equal(t, RealmCall, txType)
+	}
+
+	assert.Equal(t, 75, counts[RealmCall])
+	assert.Equal(t, 25, counts[PackageDeployment])
+}
+
+func TestMixed_PayloadSweep(t *testing.T) {
+	t.Parallel()
+
+	var (
+		transactions = uint64(120)
+		accounts     = generateAccounts(4)
+		accountKeys  = testutils.GenerateAccounts(t, 4)
+
+		sizes = []uint64{1000, 2000, 3000}
+	)
+
+	for index, account := range accounts {
+		require.NoError(t, account.SetAddress(accountKeys[index].PubKey().Address()))
+	}
+
+	// Get the runtime, with a payload sweep
+	r := GetRuntime(context.Background(), Mixed, Config{
+		Mix: []Weight{
+			{Type: BankSend, Weight: 3},
+			{Type: PackageDeployment, Weight: 1},
+		},
+		Payload: &Payload{
+			Sizes: sizes,
+			Files: 1,
+		},
+	})
+
+	txs, err := r.ConstructTransactions(
+		accountKeys,
+		accounts,
+		transactions,
+		1_000_000,
+		common.DefaultGasPrice,
+		"dummy",
+		func(_ context.Context, _ *std.Tx) (int64, error) {
+			return 100_000, nil
+		},
+	)
+	require.NoError(t, err)
+
+	require.Len(t, txs, int(transactions))
+
+	var (
+		counts = make(map[Type]int)
+		swept  = make(map[int]int)
+	)
+
+	for _, tx := range txs {
+		txType := TxType(tx)
+		counts[txType]++
+
+		if txType == PackageDeployment {
+			swept[TxPayloadSize(tx)]++
+		}
+	}
+
+	// Make sure the sweep keeps the runtime share of the mix
+	assert.Equal(t, 90, counts[BankSend])
+	assert.Equal(t, 30, counts[PackageDeployment])
+
+	// Make sure the payload sizes are swept evenly
+	for _, size := range sizes {
+		assert.Equal(t, 10, swept[int(size)])
+	}
+}
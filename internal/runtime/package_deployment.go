@@ -11,12 +11,16 @@ import (
 )
 
 type packageDeployment struct {
-	ctx context.Context
+	payload   *Payload
+	msgsPerTx int
+	ctx       context.Context
 }
 
-func newPackageDeployment(ctx context.Context) *packageDeployment {
+func newPackageDeployment(ctx context.Context, payload *Payload, msgsPerTx int) *packageDeployment {
 	return &packageDeployment{
-		ctx: ctx,
+		payload:   payload,
+		msgsPerTx: msgsPerTx,
+		ctx:       ctx,
 	}
 }
 
@@ -39,13 +43,14 @@ func (c *packageDeployment) CalculateRuntimeCosts(
 	gasPrice std.GasPrice,
 	transactions uint64,
 ) (std.Coin, error) {
-	return calculateRuntimeCosts(
+	return calculateWeightedRuntimeCosts(
 		c.ctx,
 		account,
 		transactions,
 		currentMaxGas,
 		gasPrice,
-		c.getMsgFn,
+		c.msgSources(),
+		c.msgsPerTx,
 		signFn,
 		estimateFn,
 	)
@@ -60,20 +65,20 @@ func (c *packageDeployment) ConstructTransactions(
 	chainID string,
 	estimateFn EstimateGasFn,
 ) ([]*std.Tx, error) {
-	return constructTransactions(
-		c.ctx,
-		keys,
-		accounts,
-		transactions,
-		maxGas,
-		gasPrice,
-		chainID,
-		c.getMsgFn,
-		estimateFn,
-	)
+	generator, err := c.NewGenerator(keys, accounts, maxGas, gasPrice, chainID, estimateFn)
+	if err != nil {
+		return nil, err
+	}
+
+	return generateTransactions(generator, transactions)
+}
+
+// msgSources returns the deployment message constructors, one for each swept payload size
+func (c *packageDeployment) msgSources() []weightedMsgFn {
+	return c.payload.msgSources(c.getMsgFn)
 }
 
-func (c *packageDeployment) getMsgFn(creator std.Account, index int) std.Msg {
+func (c *packageDeployment) getMsgFn(creator std.Account, index int, size uint64) (std.Msg, error) {
 	timestamp := time.Now().Unix()
 
 	memPkg := &std.MemPackage{
@@ -85,20 +90,45 @@ func (c *packageDeployment) getMsgFn(creator std.Account, index int) std.Msg {
 			timestamp,
 			index,
 		),
-		Files: []*std.MemFile{
-			{
-				Name: gnomodFileName,
-				Body: gnomodBody,
-			},
-			{
-				Name: packageFileName,
-				Body: packageBody,
+		Files: c.payload.withPayload(
+			packageName,
+			[]*std.MemFile{
+				{
+					Name: gnomodFileName,
+					Body: gnomodBody,
+				},
+				{
+					Name: packageFileName,
+					Body: packageBody,
+				},
 			},
-		},
+			size,
+		),
 	}
 
 	return vm.MsgAddPackage{
 		Creator: creator.GetAddress(),
 		Package: memPkg,
-	}
+	}, nil
+}
+
+func (c *packageDeployment) NewGenerator(
+	keys []crypto.PrivKey,
+	accounts []std.Account,
+	maxGas int64,
+	gasPrice std.GasPrice,
+	chainID string,
+	estimateFn EstimateGasFn,
+) (*Generator, error) {
+	return newWeightedGenerator(
+		c.ctx,
+		keys,
+		accounts,
+		maxGas,
+		gasPrice,
+		chainID,
+		c.msgSources(),
+		c.msgsPerTx,
+		estimateFn,
+	)
 }
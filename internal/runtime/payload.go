@@ -0,0 +1,183 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// payloadFilePrefix is the name prefix of the generated payload files
+const payloadFilePrefix = "supernova_payload_"
+
+var (
+	errInvalidPayloadSpec  = errors.New("invalid payload size specification")
+	errInvalidPayloadSize  = errors.New("invalid payload size")
+	errInvalidPayloadSteps = errors.New("invalid payload sweep steps")
+)
+
+// Payload is the generated payload deployed along the package files,
+// used for measuring how deployments scale with the package size
+type Payload struct {
+	Sizes []uint64 // the total payload sizes (bytes), swept across the deployments
+	Files uint64   // the number of files the payload is split into
+}
+
+// ParsePayloadSizes parses the payload size specification, which is either a single size
+// (ex. 4096), or a sweep across a size range, as <from>:<to>:<steps> (ex. 1024:65536:8)
+func ParsePayloadSizes(spec string) ([]uint64, error) {
+	params := strings.Split(strings.TrimSpace(spec), ":")
+
+	switch len(params) {
+	case 1:
+		size, err := parsePayloadSize(params[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return []uint64{size}, nil
+	case 3:
+		from, err := parsePayloadSize(params[0])
+		if err != nil {
+			return nil, err
+		}
+
+		to, err := parsePayloadSize(params[1])
+		if err != nil {
+			return nil, err
+		}
+
+		steps, err := strconv.ParseUint(params[2], 10, 64)
+		if err != nil || steps < 2 || to <= from {
+			return nil, fmt.Errorf("%w, %q", errInvalidPayloadSteps, spec)
+		}
+
+		// The sizes are spread evenly across the range
+		sizes := make([]uint64, 0, steps)
+		for step := uint64(0); step < steps; step++ {
+			sizes = append(sizes, from+(to-from)*step/(steps-1))
+		}
+
+		return sizes, nil
+	default:
+		return nil, fmt.Errorf("%w, %q", errInvalidPayloadSpec, spec)
+	}
+}
+
+// parsePayloadSize parses a single payload size
+func parsePayloadSize(value string) (uint64, error) {
+	size, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || size == 0 {
+		return 0, fmt.Errorf("%w, %q", errInvalidPayloadSize, value)
+	}
+
+	return size, nil
+}
+
+// payloadMsgFn defines the deployment message
+// constructor, for the given payload size
+type payloadMsgFn func(creator std.Account, index int, size uint64) (std.Msg, error)
+
+// sweptSizes returns the swept payload sizes.
+// Deployments without a payload have a single, empty (0) payload size
+func (p *Payload) sweptSizes() []uint64 {
+	if p == nil || len(p.Sizes) == 0 {
+		return []uint64{0}
+	}
+
+	return p.Sizes
+}
+
+// msgSources returns the deployment message constructors, one for each swept payload size.
+// The payload sizes are swept in a round-robin fashion, one transaction at a time,
+// and the gas of each payload size is estimated separately
+func (p *Payload) msgSources(getMsg payloadMsgFn) []weightedMsgFn {
+	var (
+		sizes   = p.sweptSizes()
+		sources = make([]weightedMsgFn, 0, len(sizes))
+	)
+
+	for _, size := range sizes {
+		sources = append(sources, weightedMsgFn{
+			getMsg: func(creator std.Account, index int) (std.Msg, error) {
+				return getMsg(creator, index, size)
+			},
+			weight: 1,
+		})
+	}
+
+	return sources
+}
+
+// withPayload adds the generated payload files
+// of the given size to the package files, if any
+func (p *Payload) withPayload(pkgName string, files []*std.MemFile, size uint64) []*std.MemFile {
+	if p == nil || size == 0 {
+		return files
+	}
+
+	var (
+		numFiles = max(p.Files, 1)
+		pkgFiles = make([]*std.MemFile, 0, len(files)+int(numFiles))
+	)
+
+	pkgFiles = append(pkgFiles, files...)
+
+	for file := uint64(0); file < numFiles; file++ {
+		// The size is split evenly across the files,
+		// with the remainder going to the first one
+		fileSize := size / numFiles
+		if file == 0 {
+			fileSize += size % numFiles
+		}
+
+		pkgFiles = append(pkgFiles, payloadFile(pkgName, file, fileSize))
+	}
+
+	// The package files need to be sorted by name
+	sort.Slice(pkgFiles, func(i, j int) bool {
+		return pkgFiles[i].Name < pkgFiles[j].Name
+	})
+
+	return pkgFiles
+}
+
+// payloadFile generates a single payload file of the given size (bytes),
+// holding a random string constant. Files can't be smaller than the constant declaration
+func payloadFile(pkgName string, index, size uint64) *std.MemFile {
+	var (
+		header = fmt.Sprintf("package %s\n\nconst payload%d = \"", pkgName, index)
+		footer = "\"\n"
+		data   = int(size) - len(header) - len(footer)
+	)
+
+	return &std.MemFile{
+		Name: fmt.Sprintf("%s%d.gno", payloadFilePrefix, index),
+		Body: header + randomString(max(data, 0)) + footer,
+	}
+}
+
+// TxPayloadSize returns the size (bytes) of the generated payload
+// deployed by the transaction, if any, summed across its messages
+func TxPayloadSize(tx *std.Tx) int {
+	size := 0
+
+	for _, txMsg := range tx.Msgs {
+		msg, ok := txMsg.(vm.MsgAddPackage)
+		if !ok || msg.Package == nil {
+			continue
+		}
+
+		for _, file := range msg.Package.Files {
+			if strings.HasPrefix(file.Name, payloadFilePrefix) {
+				size += len(file.Body)
+			}
+		}
+	}
+
+	return size
+}
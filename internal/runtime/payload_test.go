@@ -0,0 +1,206 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/supernova/internal/common"
+	testutils "github.com/gnolang/supernova/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayload_ParsePayloadSizes(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name        string
+		spec        string
+		expected    []uint64
+		expectedErr error
+	}{
+		{
+			"single size",
+			"4096",
+			[]uint64{4096},
+			nil,
+		},
+		{
+			"size sweep",
+			"1000:4000:4",
+			[]uint64{1000, 2000, 3000, 4000},
+			nil,
+		},
+		{
+			"zero size",
+			"0",
+			nil,
+			errInvalidPayloadSize,
+		},
+		{
+			"invalid sweep range",
+			"4000:1000:4",
+			nil,
+			errInvalidPayloadSteps,
+		},
+		{
+			"single sweep step",
+			"1000:4000:1",
+			nil,
+			errInvalidPayloadSteps,
+		},
+		{
+			"invalid specification",
+			"1000:4000",
+			nil,
+			errInvalidPayloadSpec,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			sizes, err := ParsePayloadSizes(testCase.spec)
+
+			assert.ErrorIs(t, err, testCase.expectedErr)
+			assert.Equal(t, testCase.expected, sizes)
+		})
+	}
+}
+
+func TestPayload_Sweep(t *testing.T) {
+	t.Parallel()
+
+	var (
+		transactions = uint64(10)
+		accounts     = generateAccounts(10)
+		accountKeys  = testutils.GenerateAccounts(t, 10)
+
+		sizes = []uint64{1000, 2001}
+	)
+
+	for _, runtimeType := range []Type{PackageDeployment, RealmDeployment} {
+		t.Run(runtimeType.String(), func(t *testing.T) {
+			t.Parallel()
+
+			// Get the runtime, with a payload sweep
+			r := GetRuntime(context.Background(), runtimeType, Config{
+				Payload: &Payload{
+					Sizes: sizes,
+					Files: 2,
+				},
+			})
+
+			// Construct the transactions
+			txs, err := r.ConstructTransactions(
+				accountKeys,
+				accounts,
+				transactions,
+				1_000_000,
+				common.DefaultGasPrice,
+				"dummy",
+				func(_ context.Context, _ *std.Tx) (int64, error) {
+					return 1_000_000, nil
+				},
+			)
+			require.NoError(t, err)
+
+			require.Len(t, txs, int(transactions))
+
+			// Make sure the payload sizes are swept
+			for index, tx := range txs {
+				assert.Equal(t, int(sizes[index%len(sizes)]), TxPayloadSize(tx))
+
+				vmMsg, ok := tx.Msgs[0].(vm.MsgAddPackage)
+				require.True(t, ok)
+
+				// The payload files are added to the package files
+				require.Len(t, vmMsg.Package.Files, 4)
+				assert.NoError(t, vmMsg.Package.ValidateBasic())
+			}
+		})
+	}
+}
+
+func TestPayload_SweepGas(t *testing.T) {
+	t.Parallel()
+
+	var (
+		transactions = uint64(9)
+		accounts     = generateAccounts(3)
+		accountKeys  = testutils.GenerateAccounts(t, 3)
+
+		sizes = []uint64{1000, 5000, 20_000}
+
+		// The deployment gas grows with the payload size
+		estimateFn = func(_ context.Context, tx *std.Tx) (int64, error) {
+			return 100_000 + 10*int64(TxPayloadSize(tx)), nil
+		}
+		sizeGas = func(size uint64) int64 {
+			return 100_000 + 10*int64(size) + gasBuffer
+		}
+	)
+
+	for _, runtimeType := range []Type{PackageDeployment, RealmDeployment} {
+		t.Run(runtimeType.String(), func(t *testing.T) {
+			t.Parallel()
+
+			r := GetRuntime(context.Background(), runtimeType, Config{
+				Payload: &Payload{
+					Sizes: sizes,
+					Files: 1,
+				},
+			})
+
+			txs, err := r.ConstructTransactions(
+				accountKeys,
+				accounts,
+				transactions,
+				10_000_000,
+				common.DefaultGasPrice,
+				"dummy",
+				estimateFn,
+			)
+			require.NoError(t, err)
+
+			// Make sure each payload size carries its own gas
+			gasWanted := make(map[int64]struct{})
+
+			for _, tx := range txs {
+				size := uint64(TxPayloadSize(tx))
+
+				assert.Equal(t, sizeGas(size), tx.Fee.GasWanted)
+
+				gasWanted[tx.Fee.GasWanted] = struct{}{}
+			}
+
+			assert.Len(t, gasWanted, len(sizes))
+
+			// Make sure the costs cover the fee of each payload size
+			cost, err := r.CalculateRuntimeCosts(
+				accounts[0],
+				estimateFn,
+				func(_ *std.Tx) error {
+					return nil
+				},
+				10_000_000,
+				common.DefaultGasPrice,
+				transactions,
+			)
+			require.NoError(t, err)
+
+			var expected int64
+
+			for _, size := range sizes {
+				fee := common.CalculateFeeInRatio(sizeGas(size), common.DefaultGasPrice)
+
+				expected += int64(transactions) / int64(len(sizes)) * fee.GasFee.Amount
+			}
+
+			assert.Equal(t, expected, cost.Amount)
+		})
+	}
+}
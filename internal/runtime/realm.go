@@ -0,0 +1,56 @@
+package runtime
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// builtinRealm is the built-in realm used by the realm runtimes
+var builtinRealm = mustParsePackageTemplate([]*std.MemFile{
+	{
+		Name: realmFileName,
+		Body: realmBody,
+	},
+})
+
+// realm returns the realm used by the realm runtimes
+func (c Config) realm() *PackageTemplate {
+	if c.Realm == nil {
+		return builtinRealm
+	}
+
+	return c.Realm
+}
+
+// newRealmPackage creates the realm package
+// with the given files, at the given path
+func newRealmPackage(path string, files []*std.MemFile) *std.MemPackage {
+	return &std.MemPackage{
+		Name:  filesPackageName(files),
+		Path:  path,
+		Files: files,
+	}
+}
+
+// filesPackageName returns the package name declared in the
+// package source files, falling back to the built-in package name
+func filesPackageName(files []*std.MemFile) string {
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name, ".gno") {
+			continue
+		}
+
+		// Gno shares the package clause syntax with Go
+		parsed, err := parser.ParseFile(token.NewFileSet(), file.Name, file.Body, parser.PackageClauseOnly)
+		if err != nil {
+			continue
+		}
+
+		return parsed.Name.Name
+	}
+
+	return packageName
+}
@@ -11,17 +11,50 @@ import (
 	"github.com/gnolang/supernova/internal/common"
 )
 
-const methodName = "SayHello"
+const (
+	methodName = "SayHello"
+
+	// realmPrefix is the name prefix of the deployed realms
+	realmPrefix = "stress_"
+)
+
+// methodArgs are the argument templates of the built-in realm method
+var methodArgs = []*Template{mustParseTemplate("Account-{{.Index}}")}
 
 type realmCall struct {
-	realmPath string
-	ctx       context.Context
+	realmPath   string
+	realm       *PackageTemplate
+	realmPrefix string // the name prefix of the deployed realm
+	deploy      bool   // flag indicating if the realm is deployed before the run
+	funcName    string
+	args        []*Template
+	msgsPerTx   int
+	ctx         context.Context
 }
 
-func newRealmCall(ctx context.Context) *realmCall {
-	return &realmCall{
-		ctx: ctx,
+func newRealmCall(ctx context.Context, cfg Config) *realmCall {
+	r := &realmCall{
+		realmPath:   cfg.RealmPath,
+		realm:       cfg.realm(),
+		realmPrefix: realmPrefix,
+		deploy:      cfg.RealmPath == "",
+		funcName:    cfg.CallFunc,
+		args:        cfg.CallArgs,
+		msgsPerTx:   cfg.msgsPerTx(),
+		ctx:         ctx,
+	}
+
+	if r.funcName == "" {
+		r.funcName = methodName
 	}
+
+	// The built-in method arguments are used
+	// only if no custom call is specified
+	if cfg.CallFunc == "" && len(cfg.CallArgs) == 0 {
+		r.args = methodArgs
+	}
+
+	return r
 }
 
 func (r *realmCall) Initialize(
@@ -31,32 +64,30 @@ func (r *realmCall) Initialize(
 	currentMaxGas int64,
 	gasPrice std.GasPrice,
 ) ([]*std.Tx, error) {
+	// Existing on-chain realms are called directly
+	if !r.deploy {
+		return nil, nil
+	}
+
 	// The Realm needs to be deployed before
 	// it can be interacted with
 	r.realmPath = fmt.Sprintf(
-		"%s/%s/stress_%d",
+		"%s/%s/%s%d",
 		realmPathPrefix,
 		account.GetAddress().String(),
+		r.realmPrefix,
 		time.Now().Unix(),
 	)
 
+	files, err := r.realm.render(account.GetAddress().String(), 0)
+	if err != nil {
+		return nil, fmt.Errorf("unable to render realm, %w", err)
+	}
+
 	// Construct the transaction
 	msg := vm.MsgAddPackage{
 		Creator: account.GetAddress(),
-		Package: &std.MemPackage{
-			Name: packageName,
-			Path: r.realmPath,
-			Files: []*std.MemFile{
-				{
-					Name: gnomodFileName,
-					Body: gnomodBody,
-				},
-				{
-					Name: realmFileName,
-					Body: realmBody,
-				},
-			},
-		},
+		Package: newRealmPackage(r.realmPath, files),
 	}
 
 	tx := &std.Tx{
@@ -65,7 +96,7 @@ func (r *realmCall) Initialize(
 		Fee: common.CalculateFeeInRatio(currentMaxGas, gasPrice),
 	}
 
-	err := signFn(tx)
+	err = signFn(tx)
 	if err != nil {
 		return nil, fmt.Errorf("unable to sign initialize transaction, %w", err)
 	}
@@ -104,6 +135,7 @@ func (r *realmCall) CalculateRuntimeCosts(
 		currentMaxGas,
 		gasPrice,
 		r.getMsgFn,
+		r.msgsPerTx,
 		signFn,
 		estimateFn,
 	)
@@ -127,15 +159,51 @@ func (r *realmCall) ConstructTransactions(
 		gasPrice,
 		chainID,
 		r.getMsgFn,
+		r.msgsPerTx,
 		estimateFn,
 	)
 }
 
-func (r *realmCall) getMsgFn(creator std.Account, index int) std.Msg {
+func (r *realmCall) deployedRealm() string {
+	return r.realmPath
+}
+
+// msgSources returns the message constructor of the runtime
+func (r *realmCall) msgSources() []weightedMsgFn {
+	return singleSource(r.getMsgFn)
+}
+
+func (r *realmCall) getMsgFn(creator std.Account, index int) (std.Msg, error) {
+	args, err := renderTemplates(r.args, creator.GetAddress().String(), index)
+	if err != nil {
+		return nil, err
+	}
+
 	return vm.MsgCall{
 		Caller:  creator.GetAddress(),
 		PkgPath: r.realmPath,
-		Func:    methodName,
-		Args:    []string{fmt.Sprintf("Account-%d", index)},
-	}
+		Func:    r.funcName,
+		Args:    args,
+	}, nil
+}
+
+func (r *realmCall) NewGenerator(
+	keys []crypto.PrivKey,
+	accounts []std.Account,
+	maxGas int64,
+	gasPrice std.GasPrice,
+	chainID string,
+	estimateFn EstimateGasFn,
+) (*Generator, error) {
+	return newGenerator(
+		r.ctx,
+		keys,
+		accounts,
+		maxGas,
+		gasPrice,
+		chainID,
+		r.getMsgFn,
+		r.msgsPerTx,
+		estimateFn,
+	)
 }
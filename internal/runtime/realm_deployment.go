@@ -11,12 +11,23 @@ import (
 )
 
 type realmDeployment struct {
-	ctx context.Context
+	realm     *PackageTemplate
+	payload   *Payload
+	msgsPerTx int
+	ctx       context.Context
 }
 
-func newRealmDeployment(ctx context.Context) *realmDeployment {
+func newRealmDeployment(
+	ctx context.Context,
+	realm *PackageTemplate,
+	payload *Payload,
+	msgsPerTx int,
+) *realmDeployment {
 	return &realmDeployment{
-		ctx: ctx,
+		realm:     realm,
+		payload:   payload,
+		msgsPerTx: msgsPerTx,
+		ctx:       ctx,
 	}
 }
 
@@ -39,45 +50,47 @@ func (c *realmDeployment) CalculateRuntimeCosts(
 	gasPrice std.GasPrice,
 	transactions uint64,
 ) (std.Coin, error) {
-	return calculateRuntimeCosts(
+	return calculateWeightedRuntimeCosts(
 		c.ctx,
 		account,
 		transactions,
 		currentMaxGas,
 		gasPrice,
-		c.getMsgFn,
+		c.msgSources(),
+		c.msgsPerTx,
 		signFn,
 		estimateFn,
 	)
 }
 
-func (c *realmDeployment) getMsgFn(creator std.Account, index int) std.Msg {
+// msgSources returns the deployment message constructors, one for each swept payload size
+func (c *realmDeployment) msgSources() []weightedMsgFn {
+	return c.payload.msgSources(c.getMsgFn)
+}
+
+func (c *realmDeployment) getMsgFn(creator std.Account, index int, size uint64) (std.Msg, error) {
 	timestamp := time.Now().Unix()
-	memPkg := &std.MemPackage{
-		Name: packageName,
-		Path: fmt.Sprintf(
+
+	files, err := c.realm.render(creator.GetAddress().String(), index)
+	if err != nil {
+		return nil, err
+	}
+
+	memPkg := newRealmPackage(
+		fmt.Sprintf(
 			"%s/%s/stress_%d_%d",
 			realmPathPrefix,
 			creator.GetAddress().String(),
 			timestamp,
 			index,
 		),
-		Files: []*std.MemFile{
-			{
-				Name: gnomodFileName,
-				Body: gnomodBody,
-			},
-			{
-				Name: realmFileName,
-				Body: realmBody,
-			},
-		},
-	}
+		c.payload.withPayload(filesPackageName(files), files, size),
+	)
 
 	return vm.MsgAddPackage{
 		Creator: creator.GetAddress(),
 		Package: memPkg,
-	}
+	}, nil
 }
 
 func (c *realmDeployment) ConstructTransactions(
@@ -89,15 +102,31 @@ func (c *realmDeployment) ConstructTransactions(
 	chainID string,
 	estimateFn EstimateGasFn,
 ) ([]*std.Tx, error) {
-	return constructTransactions(
+	generator, err := c.NewGenerator(keys, accounts, maxGas, gasPrice, chainID, estimateFn)
+	if err != nil {
+		return nil, err
+	}
+
+	return generateTransactions(generator, transactions)
+}
+
+func (c *realmDeployment) NewGenerator(
+	keys []crypto.PrivKey,
+	accounts []std.Account,
+	maxGas int64,
+	gasPrice std.GasPrice,
+	chainID string,
+	estimateFn EstimateGasFn,
+) (*Generator, error) {
+	return newWeightedGenerator(
 		c.ctx,
 		keys,
 		accounts,
-		transactions,
 		maxGas,
 		gasPrice,
 		chainID,
-		c.getMsgFn,
+		c.msgSources(),
+		c.msgsPerTx,
 		estimateFn,
 	)
 }
@@ -0,0 +1,204 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"path"
+	"strings"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+const (
+	storageSetMethod    = "Set"
+	storageDeleteMethod = "Delete"
+
+	// storageRealmPrefix is the name prefix of the deployed storage realms
+	storageRealmPrefix = "storage_"
+
+	// storageEntryOverhead is the estimated storage size
+	// of a single stored entry, besides its value (bytes).
+	// It covers the key, the tree node and the object metadata
+	storageEntryOverhead = 1_000
+
+	// storageGasHeadroom is the gas headroom (percentage) of the storage calls.
+	// The gas is estimated on an empty store, and the inserts
+	// get more expensive as the stored tree grows deeper
+	storageGasHeadroom = 20
+)
+
+const (
+	defaultStorageKeys      = 10_000
+	defaultStorageValueSize = 256
+
+	// DefaultStoragePrice is the default storage deposit
+	// price of the chain, in ugnot per byte
+	DefaultStoragePrice = 100
+)
+
+// Storage is the storage realm workload configuration
+type Storage struct {
+	Keys      uint64 // the size of the key space
+	ValueSize uint64 // the size of the stored values (bytes)
+	Deletes   uint64 // the share of calls that delete a key (percentage)
+	Price     uint64 // the storage deposit price of the chain (ugnot per byte)
+}
+
+// realmStorage is the runtime that grows the on-chain realm state,
+// by inserting, updating and deleting keys of a deployed storage realm
+type realmStorage struct {
+	*realmCall
+
+	storage Storage
+}
+
+func newRealmStorage(ctx context.Context, storage Storage, msgsPerTx int) *realmStorage {
+	if storage.Keys == 0 {
+		storage.Keys = defaultStorageKeys
+	}
+
+	if storage.ValueSize == 0 {
+		storage.ValueSize = defaultStorageValueSize
+	}
+
+	return &realmStorage{
+		realmCall: &realmCall{
+			realm: mustParsePackageTemplate([]*std.MemFile{
+				{
+					Name: storageFileName,
+					Body: storageRealmBody,
+				},
+			}),
+			realmPrefix: storageRealmPrefix,
+			deploy:      true,
+			msgsPerTx:   msgsPerTx,
+			ctx:         ctx,
+		},
+		storage: storage,
+	}
+}
+
+func (r *realmStorage) CalculateRuntimeCosts(
+	account std.Account,
+	estimateFn EstimateGasFn,
+	signFn SignFn,
+	currentMaxGas int64,
+	gasPrice std.GasPrice,
+	transactions uint64,
+) (std.Coin, error) {
+	cost, err := calculateWeightedRuntimeCosts(
+		r.ctx,
+		account,
+		transactions,
+		currentMaxGas,
+		gasPrice,
+		r.msgSources(),
+		r.msgsPerTx,
+		signFn,
+		estimateFn,
+	)
+	if err != nil {
+		return std.Coin{}, err
+	}
+
+	// Besides the fees, each account needs to cover the storage
+	// deposit locked for the entries it inserts (in the worst case)
+	entrySize := int64(r.storage.ValueSize) + storageEntryOverhead
+	cost.Amount += int64(transactions) * int64(r.msgsPerTx) * entrySize * int64(r.storage.Price)
+
+	return cost, nil
+}
+
+func (r *realmStorage) ConstructTransactions(
+	keys []crypto.PrivKey,
+	accounts []std.Account,
+	transactions uint64,
+	maxGas int64,
+	gasPrice std.GasPrice,
+	chainID string,
+	estimateFn EstimateGasFn,
+) ([]*std.Tx, error) {
+	generator, err := r.NewGenerator(keys, accounts, maxGas, gasPrice, chainID, estimateFn)
+	if err != nil {
+		return nil, err
+	}
+
+	return generateTransactions(generator, transactions)
+}
+
+func (r *realmStorage) NewGenerator(
+	keys []crypto.PrivKey,
+	accounts []std.Account,
+	maxGas int64,
+	gasPrice std.GasPrice,
+	chainID string,
+	estimateFn EstimateGasFn,
+) (*Generator, error) {
+	return newWeightedGenerator(
+		r.ctx,
+		keys,
+		accounts,
+		maxGas,
+		gasPrice,
+		chainID,
+		r.msgSources(),
+		r.msgsPerTx,
+		estimateFn,
+	)
+}
+
+// msgSources returns the message constructor of the runtime.
+// The gas is estimated with an insert, the most expensive call,
+// with the headroom for the growing store
+func (r *realmStorage) msgSources() []weightedMsgFn {
+	return []weightedMsgFn{
+		{
+			getMsg:      r.getMsgFn,
+			estimateMsg: r.estimateMsgFn,
+			weight:      1,
+			headroom:    storageGasHeadroom,
+		},
+	}
+}
+
+// getMsgFn constructs a call that writes a random key of the key space,
+// or deletes it. Early in the run most writes are inserts, which turn into
+// updates as the key space fills up
+func (r *realmStorage) getMsgFn(creator std.Account, _ int) (std.Msg, error) {
+	//nolint:gosec // The keys don't need to be cryptographically random
+	key := fmt.Sprintf("key-%d", rand.Int63n(int64(r.storage.Keys)))
+
+	msg := vm.MsgCall{
+		Caller:  creator.GetAddress(),
+		PkgPath: r.realmPath,
+		Func:    storageSetMethod,
+		Args:    []string{key, randomString(int(r.storage.ValueSize))},
+	}
+
+	//nolint:gosec // The operations don't need to be cryptographically random
+	if uint64(rand.Intn(100)) < r.storage.Deletes {
+		msg.Func = storageDeleteMethod
+		msg.Args = []string{key}
+	}
+
+	return msg, nil
+}
+
+// estimateMsgFn constructs a call that inserts a fresh key,
+// outside the key space, so it is never a delete or an update
+func (r *realmStorage) estimateMsgFn(creator std.Account, index int) (std.Msg, error) {
+	return vm.MsgCall{
+		Caller:  creator.GetAddress(),
+		PkgPath: r.realmPath,
+		Func:    storageSetMethod,
+		Args:    []string{fmt.Sprintf("estimate-%d", index), randomString(int(r.storage.ValueSize))},
+	}, nil
+}
+
+// isStorageRealm checks if the realm at the given path is a storage realm
+func isStorageRealm(realmPath string) bool {
+	return strings.HasPrefix(path.Base(realmPath), storageRealmPrefix)
+}
@@ -0,0 +1,140 @@
+package runtime
+
+import (
+	"context"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// builtinScript is the built-in script executed by the run runtime
+var builtinScript = mustParseScriptTemplate([]*std.MemFile{
+	{
+		Name: scriptFileName,
+		Body: scriptBody,
+	},
+})
+
+// script returns the script executed by the run runtime
+func (c Config) script() *PackageTemplate {
+	if c.Script == nil {
+		return builtinScript
+	}
+
+	return c.Script
+}
+
+// run is the runtime that executes throwaway main packages (MsgRun),
+// which are type-checked, compiled and executed on every transaction
+type run struct {
+	ctx       context.Context
+	script    *PackageTemplate
+	msgsPerTx int
+}
+
+func newRun(ctx context.Context, script *PackageTemplate, msgsPerTx int) *run {
+	return &run{
+		ctx:       ctx,
+		script:    script,
+		msgsPerTx: msgsPerTx,
+	}
+}
+
+func (r *run) Initialize(
+	_ std.Account,
+	_ SignFn,
+	_ EstimateGasFn,
+	_ int64,
+	_ std.GasPrice,
+) ([]*std.Tx, error) {
+	// No extra setup needed for this runtime type
+	return nil, nil
+}
+
+func (r *run) CalculateRuntimeCosts(
+	account std.Account,
+	estimateFn EstimateGasFn,
+	signFn SignFn,
+	currentMaxGas int64,
+	gasPrice std.GasPrice,
+	transactions uint64,
+) (std.Coin, error) {
+	return calculateRuntimeCosts(
+		r.ctx,
+		account,
+		transactions,
+		currentMaxGas,
+		gasPrice,
+		r.getMsgFn,
+		r.msgsPerTx,
+		signFn,
+		estimateFn,
+	)
+}
+
+func (r *run) ConstructTransactions(
+	keys []crypto.PrivKey,
+	accounts []std.Account,
+	transactions uint64,
+	maxGas int64,
+	gasPrice std.GasPrice,
+	chainID string,
+	estimateFn EstimateGasFn,
+) ([]*std.Tx, error) {
+	return constructTransactions(
+		r.ctx,
+		keys,
+		accounts,
+		transactions,
+		maxGas,
+		gasPrice,
+		chainID,
+		r.getMsgFn,
+		r.msgsPerTx,
+		estimateFn,
+	)
+}
+
+func (r *run) NewGenerator(
+	keys []crypto.PrivKey,
+	accounts []std.Account,
+	maxGas int64,
+	gasPrice std.GasPrice,
+	chainID string,
+	estimateFn EstimateGasFn,
+) (*Generator, error) {
+	return newGenerator(
+		r.ctx,
+		keys,
+		accounts,
+		maxGas,
+		gasPrice,
+		chainID,
+		r.getMsgFn,
+		r.msgsPerTx,
+		estimateFn,
+	)
+}
+
+// msgSources returns the message constructor of the runtime
+func (r *run) msgSources() []weightedMsgFn {
+	return singleSource(r.getMsgFn)
+}
+
+func (r *run) getMsgFn(creator std.Account, index int) (std.Msg, error) {
+	files, err := r.script.render(creator.GetAddress().String(), index)
+	if err != nil {
+		return nil, err
+	}
+
+	return vm.MsgRun{
+		Caller: creator.GetAddress(),
+		Package: &std.MemPackage{
+			Name: scriptName,
+			// The path is set by the VM, to the caller's run path
+			Path:  "",
+			Files: files,
+		},
+	}, nil
+}
@@ -12,6 +12,61 @@ const (
 	packagePathPrefix = "gno.land/p"
 )
 
+// Config is the runtime configuration
+type Config struct {
+	// Realm is the realm used by the realm runtimes.
+	// If unset, the built-in realm is used
+	Realm *PackageTemplate
+
+	// RealmPath is the path of the existing on-chain realm
+	// called by the realm call runtime. If set, no realm is deployed
+	RealmPath string
+
+	// CallFunc is the realm function called by the realm call runtime.
+	// If unset, the built-in realm function is called
+	CallFunc string
+
+	// CallArgs are the argument templates of the called realm function
+	CallArgs []*Template
+
+	// Script is the main package executed by the run runtime.
+	// If unset, the built-in script is used
+	Script *PackageTemplate
+
+	// Payload is the generated payload deployed by the
+	// package and realm deployment runtimes, if any
+	Payload *Payload
+
+	// Storage is the workload configuration
+	// of the storage realm runtime
+	Storage Storage
+
+	// Mix is the weighted list of runtimes
+	// combined by the mixed runtime
+	Mix []Weight
+
+	// TransferPattern is the pattern of the bank transfers.
+	// If unset, the ring pattern is used
+	TransferPattern TransferPattern
+
+	// TransferAmount is the amount (in ugnot)
+	// of a single bank transfer
+	TransferAmount int64
+
+	// MsgsPerTx is the number of messages in a single transaction.
+	// If unset, each transaction holds a single message
+	MsgsPerTx uint64
+
+	// History is the source chain transaction
+	// history replayed by the history runtime
+	History *TxHistory
+}
+
+// msgsPerTx returns the number of messages in a single transaction
+func (c Config) msgsPerTx() int {
+	return max(int(c.MsgsPerTx), 1)
+}
+
 // EstimateGasFn is the gas estimation callback
 type EstimateGasFn func(ctx context.Context, tx *std.Tx) (int64, error)
 
@@ -35,7 +90,8 @@ type Runtime interface {
 	) ([]*std.Tx, error)
 	// CalculateRuntimeCosts calculates the amount of funds
 	// each account needs to have in order to participate in the
-	// stress test run
+	// stress test run, sending out the given number of transactions.
+	// The cost covers the transaction fees, and any value the transactions transfer
 	CalculateRuntimeCosts(
 		account std.Account,
 		estimateFn EstimateGasFn,
@@ -56,17 +112,55 @@ type Runtime interface {
 		chainID string,
 		estimateFn EstimateGasFn,
 	) ([]*std.Tx, error)
+
+	// NewGenerator creates a transaction generator that
+	// constructs and signs stress test transactions on demand
+	NewGenerator(
+		keys []crypto.PrivKey,
+		accounts []std.Account,
+		maxGas int64,
+		gasPrice std.GasPrice,
+		chainID string,
+		estimateFn EstimateGasFn,
+	) (*Generator, error)
+}
+
+// realmRuntime is a runtime that interacts with a realm
+type realmRuntime interface {
+	deployedRealm() string
+}
+
+// DeployedRealm returns the path of the realm the runtime
+// interacts with, if any. Realms deployed by the runtime
+// are known only after the runtime is initialized
+func DeployedRealm(r Runtime) string {
+	withRealm, ok := r.(realmRuntime)
+	if !ok {
+		return ""
+	}
+
+	return withRealm.deployedRealm()
 }
 
 // GetRuntime fetches the specified runtime, if any
-func GetRuntime(ctx context.Context, runtimeType Type) Runtime {
+func GetRuntime(ctx context.Context, runtimeType Type, cfg Config) Runtime {
 	switch runtimeType {
 	case RealmCall:
-		return newRealmCall(ctx)
+		return newRealmCall(ctx, cfg)
+	case RealmStorage:
+		return newRealmStorage(ctx, cfg.Storage, cfg.msgsPerTx())
+	case Run:
+		return newRun(ctx, cfg.script(), cfg.msgsPerTx())
 	case RealmDeployment:
-		return newRealmDeployment(ctx)
+		return newRealmDeployment(ctx, cfg.realm(), cfg.Payload, cfg.msgsPerTx())
 	case PackageDeployment:
-		return newPackageDeployment(ctx)
+		return newPackageDeployment(ctx, cfg.Payload, cfg.msgsPerTx())
+	case BankSend:
+		return newBankSend(ctx, cfg.TransferPattern, cfg.TransferAmount, cfg.msgsPerTx())
+	case Mixed:
+		return newMixed(ctx, cfg)
+	case History:
+		return newHistoryReplay(ctx, cfg.History)
 	default:
 		return nil
 	}
@@ -2,13 +2,17 @@ package runtime
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
 	"github.com/gnolang/gno/tm2/pkg/std"
 	"github.com/gnolang/supernova/internal/common"
 	testutils "github.com/gnolang/supernova/internal/testing"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // verifyDeployTxCommon does common transaction verification
@@ -71,7 +75,7 @@ func TestRuntime_CommonDeployment(t *testing.T) {
 			)
 
 			// Get the runtime
-			r := GetRuntime(context.Background(), testCase.mode)
+			r := GetRuntime(context.Background(), testCase.mode, Config{})
 
 			// Make sure there is no initialization logic
 			initialTxs, err := r.Initialize(
@@ -127,7 +131,7 @@ func TestRuntime_RealmCall(t *testing.T) {
 	)
 
 	// Get the runtime
-	r := GetRuntime(context.Background(), RealmCall)
+	r := GetRuntime(context.Background(), RealmCall, Config{})
 
 	// Make sure the initialization logic is present
 	initialTxs, err := r.Initialize(
@@ -205,3 +209,741 @@ func TestRuntime_RealmCall(t *testing.T) {
 		)
 	}
 }
+
+func TestRuntime_RealmSource(t *testing.T) {
+	t.Parallel()
+
+	var (
+		transactions = uint64(10)
+		accounts     = generateAccounts(10)
+		accountKeys  = testutils.GenerateAccounts(t, 10)
+
+		realmSource = "package counter\n\nvar counter int\n"
+	)
+
+	realm, err := ParsePackageTemplate([]*std.MemFile{
+		{
+			Name: "realm.gno",
+			Body: realmSource,
+		},
+	})
+	require.NoError(t, err)
+
+	// Get the runtime, with a custom realm
+	r := GetRuntime(context.Background(), RealmDeployment, Config{Realm: realm})
+
+	// Construct the transactions
+	txs, err := r.ConstructTransactions(
+		accountKeys,
+		accounts,
+		transactions,
+		1_000_000,
+		common.DefaultGasPrice,
+		"dummy",
+		func(_ context.Context, _ *std.Tx) (int64, error) {
+			return 1_000_000, nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("unable to construct transactions, %v", err)
+	}
+
+	// Make sure the custom realm is deployed
+	for _, tx := range txs {
+		verifyDeployTxCommon(t, tx, realmPathPrefix)
+
+		vmMsg, ok := tx.Msgs[0].(vm.MsgAddPackage)
+		if !ok {
+			t.Fatal("invalid tx message type")
+		}
+
+		assert.Equal(t, "counter", vmMsg.Package.Name)
+		assert.Equal(t, gnomodFileName, vmMsg.Package.Files[0].Name)
+		assert.Equal(t, realmSource, vmMsg.Package.Files[1].Body)
+	}
+}
+
+func TestRuntime_RealmTemplate(t *testing.T) {
+	t.Parallel()
+
+	var (
+		transactions = uint64(10)
+		accounts     = generateAccounts(10)
+		accountKeys  = testutils.GenerateAccounts(t, 10)
+	)
+
+	for index, account := range accounts {
+		require.NoError(t, account.SetAddress(accountKeys[index].PubKey().Address()))
+	}
+
+	realm, err := ParsePackageTemplate([]*std.MemFile{
+		{
+			Name: "realm.gno.tmpl",
+			Body: "package counter\n\nvar owner = \"{{.Address}}\"\n",
+		},
+	})
+	require.NoError(t, err)
+
+	// Get the runtime, with a templated realm
+	r := GetRuntime(context.Background(), RealmDeployment, Config{Realm: realm})
+
+	// Construct the transactions
+	txs, err := r.ConstructTransactions(
+		accountKeys,
+		accounts,
+		transactions,
+		1_000_000,
+		common.DefaultGasPrice,
+		"dummy",
+		func(_ context.Context, _ *std.Tx) (int64, error) {
+			return 1_000_000, nil
+		},
+	)
+	require.NoError(t, err)
+
+	// Make sure the realm is rendered for each deployment
+	for _, tx := range txs {
+		verifyDeployTxCommon(t, tx, realmPathPrefix)
+
+		vmMsg, ok := tx.Msgs[0].(vm.MsgAddPackage)
+		require.True(t, ok)
+
+		assert.Equal(t, "realm.gno", vmMsg.Package.Files[1].Name)
+		assert.Equal(
+			t,
+			fmt.Sprintf("package counter\n\nvar owner = %q\n", vmMsg.Creator.String()),
+			vmMsg.Package.Files[1].Body,
+		)
+	}
+}
+
+func TestRuntime_RealmCallTarget(t *testing.T) {
+	t.Parallel()
+
+	var (
+		transactions = uint64(10)
+		accounts     = generateAccounts(10)
+		accountKeys  = testutils.GenerateAccounts(t, 10)
+
+		realmPath = "gno.land/r/demo/counter"
+		callFunc  = "Increment"
+	)
+
+	args, err := ParseTemplates([]string{"key-{{.Index}}", "{{.Address}}"})
+	require.NoError(t, err)
+
+	// Get the runtime, with an existing on-chain realm
+	r := GetRuntime(context.Background(), RealmCall, Config{
+		RealmPath: realmPath,
+		CallFunc:  callFunc,
+		CallArgs:  args,
+	})
+
+	// Make sure the existing realm is not deployed
+	initialTxs, err := r.Initialize(
+		accounts[0],
+		func(_ *std.Tx) error {
+			return nil
+		},
+		func(_ context.Context, _ *std.Tx) (int64, error) {
+			return 1_000_000, nil
+		},
+		1_000_000,
+		common.DefaultGasPrice,
+	)
+	require.NoError(t, err)
+
+	assert.Empty(t, initialTxs)
+
+	// Make sure the existing realm is the runtime realm
+	assert.Equal(t, realmPath, DeployedRealm(r))
+
+	// Construct the transactions
+	txs, err := r.ConstructTransactions(
+		accountKeys,
+		accounts,
+		transactions,
+		1_000_000,
+		common.DefaultGasPrice,
+		"dummy",
+		func(_ context.Context, _ *std.Tx) (int64, error) {
+			return 1_000_000, nil
+		},
+	)
+	require.NoError(t, err)
+
+	require.Len(t, txs, int(transactions))
+
+	// Make sure the custom call is constructed
+	for _, tx := range txs {
+		vmMsg, ok := tx.Msgs[0].(vm.MsgCall)
+		require.True(t, ok)
+
+		assert.Equal(t, realmPath, vmMsg.PkgPath)
+		assert.Equal(t, callFunc, vmMsg.Func)
+
+		require.Len(t, vmMsg.Args, 2)
+
+		assert.Contains(t, vmMsg.Args[0], "key-")
+		assert.Equal(t, vmMsg.Caller.String(), vmMsg.Args[1])
+	}
+}
+
+func TestRuntime_BankSend(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name    string
+		pattern TransferPattern
+	}{
+		{
+			"ring transfers",
+			RingTransfer,
+		},
+		{
+			"random transfers",
+			RandomTransfer,
+		},
+		{
+			"fan-in transfers",
+			FanInTransfer,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				transactions = uint64(20)
+				amount       = int64(5)
+				accounts     = generateAccounts(4)
+				accountKeys  = testutils.GenerateAccounts(t, 4)
+
+				estimateFn = func(_ context.Context, _ *std.Tx) (int64, error) {
+					return 1_000_000, nil
+				}
+			)
+
+			// Assign the account addresses, so the transfers can be told apart
+			for index, account := range accounts {
+				require.NoError(t, account.SetAddress(accountKeys[index].PubKey().Address()))
+			}
+
+			// Get the runtime
+			r := GetRuntime(context.Background(), BankSend, Config{
+				TransferPattern: testCase.pattern,
+				TransferAmount:  amount,
+			})
+
+			// Make sure the costs cover the transferred amounts
+			cost, err := r.CalculateRuntimeCosts(
+				accounts[0],
+				estimateFn,
+				func(_ *std.Tx) error {
+					return nil
+				},
+				1_000_000,
+				common.DefaultGasPrice,
+				transactions,
+			)
+			require.NoError(t, err)
+
+			// Each transaction pays the fee for the estimated gas (with the buffer)
+			txFee := common.CalculateFeeInRatio(1_000_000+gasBuffer, common.DefaultGasPrice).GasFee.Amount
+
+			assert.Equal(t, int64(transactions)*(txFee+amount), cost.Amount)
+
+			// Construct the transactions
+			txs, err := r.ConstructTransactions(
+				accountKeys,
+				accounts,
+				transactions,
+				1_000_000,
+				common.DefaultGasPrice,
+				"dummy",
+				estimateFn,
+			)
+			require.NoError(t, err)
+
+			require.Len(t, txs, int(transactions))
+
+			for index, tx := range txs {
+				require.Len(t, tx.Msgs, 1)
+
+				msg, ok := tx.Msgs[0].(bank.MsgSend)
+				require.True(t, ok)
+
+				// Make sure the transfer params are valid
+				sender := accounts[index%len(accounts)].GetAddress()
+
+				assert.Equal(t, sender, msg.FromAddress)
+				assert.Equal(t, std.NewCoins(std.NewCoin(common.Denomination, amount)), msg.Amount)
+
+				switch testCase.pattern {
+				case RingTransfer:
+					assert.Equal(t, accounts[(index+1)%len(accounts)].GetAddress(), msg.ToAddress)
+				case RandomTransfer:
+					assert.NotEqual(t, sender, msg.ToAddress)
+				case FanInTransfer:
+					assert.Equal(t, accounts[0].GetAddress(), msg.ToAddress)
+				}
+			}
+		})
+	}
+}
+
+func TestRuntime_RealmStorage(t *testing.T) {
+	t.Parallel()
+
+	var (
+		transactions = uint64(100)
+		accounts     = generateAccounts(11)
+		accountKeys  = testutils.GenerateAccounts(t, 11)
+
+		storage = Storage{
+			Keys:      10,
+			ValueSize: 32,
+			Deletes:   50,
+			Price:     DefaultStoragePrice,
+		}
+
+		estimateFn = func(_ context.Context, _ *std.Tx) (int64, error) {
+			return 1_000_000, nil
+		}
+	)
+
+	// Get the runtime
+	r := GetRuntime(context.Background(), RealmStorage, Config{Storage: storage})
+
+	// Make sure the storage realm is deployed
+	initialTxs, err := r.Initialize(
+		accounts[0],
+		func(_ *std.Tx) error {
+			return nil
+		},
+		estimateFn,
+		1_000_000,
+		common.DefaultGasPrice,
+	)
+	require.NoError(t, err)
+
+	require.Len(t, initialTxs, 1)
+	verifyDeployTxCommon(t, initialTxs[0], realmPathPrefix)
+
+	deployMsg, ok := initialTxs[0].Msgs[0].(vm.MsgAddPackage)
+	require.True(t, ok)
+
+	assert.Equal(t, "storage", deployMsg.Package.Name)
+
+	// Make sure the costs cover the storage deposits
+	cost, err := r.CalculateRuntimeCosts(
+		accounts[0],
+		estimateFn,
+		func(_ *std.Tx) error {
+			return nil
+		},
+		1_000_000,
+		common.DefaultGasPrice,
+		transactions,
+	)
+	require.NoError(t, err)
+
+	// The estimated gas carries the storage headroom
+	gasWanted := int64(1_000_000 * (100 + storageGasHeadroom) / 100)
+	txFee := common.CalculateFeeInRatio(gasWanted+gasBuffer, common.DefaultGasPrice).GasFee.Amount
+
+	assert.Equal(
+		t,
+		int64(transactions)*(txFee+(32+storageEntryOverhead)*DefaultStoragePrice),
+		cost.Amount,
+	)
+
+	// Construct the transactions
+	txs, err := r.ConstructTransactions(
+		accountKeys[1:],
+		accounts[1:],
+		transactions,
+		10_000_000,
+		common.DefaultGasPrice,
+		"dummy",
+		estimateFn,
+	)
+	require.NoError(t, err)
+
+	require.Len(t, txs, int(transactions))
+
+	// Make sure the keys are written and deleted
+	for _, tx := range txs {
+		assert.Equal(t, RealmStorage, TxType(tx))
+		assert.Equal(t, gasWanted+gasBuffer, tx.Fee.GasWanted)
+
+		vmMsg, ok := tx.Msgs[0].(vm.MsgCall)
+		require.True(t, ok)
+
+		assert.Equal(t, deployMsg.Package.Path, vmMsg.PkgPath)
+		assert.Regexp(t, `^key-\d$`, vmMsg.Args[0])
+
+		switch vmMsg.Func {
+		case storageSetMethod:
+			require.Len(t, vmMsg.Args, 2)
+			assert.Len(t, vmMsg.Args[1], int(storage.ValueSize))
+		case storageDeleteMethod:
+			assert.Len(t, vmMsg.Args, 1)
+		default:
+			t.Fatalf("invalid storage method %s", vmMsg.Func)
+		}
+	}
+}
+
+func TestRuntime_RealmStorageEstimate(t *testing.T) {
+	t.Parallel()
+
+	var (
+		accounts    = generateAccounts(2)
+		accountKeys = testutils.GenerateAccounts(t, 2)
+
+		// Every call of the run is a delete
+		storage = Storage{
+			Keys:      10,
+			ValueSize: 32,
+			Deletes:   100,
+		}
+
+		estimated []vm.MsgCall
+	)
+
+	estimateFn := func(_ context.Context, tx *std.Tx) (int64, error) {
+		msg, ok := tx.Msgs[0].(vm.MsgCall)
+		if ok {
+			estimated = append(estimated, msg)
+		}
+
+		return 1_000_000, nil
+	}
+
+	r := GetRuntime(context.Background(), RealmStorage, Config{Storage: storage})
+
+	_, err := r.Initialize(
+		accounts[0],
+		func(_ *std.Tx) error {
+			return nil
+		},
+		estimateFn,
+		1_000_000,
+		common.DefaultGasPrice,
+	)
+	require.NoError(t, err)
+
+	txs, err := r.ConstructTransactions(
+		accountKeys[1:],
+		accounts[1:],
+		10,
+		10_000_000,
+		common.DefaultGasPrice,
+		"dummy",
+		estimateFn,
+	)
+	require.NoError(t, err)
+
+	for _, tx := range txs {
+		vmMsg, ok := tx.Msgs[0].(vm.MsgCall)
+		require.True(t, ok)
+
+		assert.Equal(t, storageDeleteMethod, vmMsg.Func)
+	}
+
+	// Make sure the gas is estimated with an insert of a fresh key
+	require.Len(t, estimated, 1)
+
+	assert.Equal(t, storageSetMethod, estimated[0].Func)
+	require.Len(t, estimated[0].Args, 2)
+	assert.NotRegexp(t, `^key-\d+$`, estimated[0].Args[0])
+	assert.Len(t, estimated[0].Args[1], int(storage.ValueSize))
+}
+
+func TestRuntime_Run(t *testing.T) {
+	t.Parallel()
+
+	var (
+		transactions = uint64(10)
+		accounts     = generateAccounts(10)
+		accountKeys  = testutils.GenerateAccounts(t, 10)
+
+		estimateFn = func(_ context.Context, _ *std.Tx) (int64, error) {
+			return 1_000_000, nil
+		}
+	)
+
+	for index, account := range accounts {
+		require.NoError(t, account.SetAddress(accountKeys[index].PubKey().Address()))
+	}
+
+	script, err := ParseScriptTemplate([]*std.MemFile{
+		{
+			Name: "main.gno.tmpl",
+			Body: "package main\n\nfunc main() {\n\tprintln(\"{{.Address}}\", {{.Index}})\n}\n",
+		},
+	})
+	require.NoError(t, err)
+
+	testTable := []struct {
+		name   string
+		script *PackageTemplate
+		body   func(caller string, index int) string
+	}{
+		{
+			"built-in script",
+			nil,
+			func(_ string, _ int) string {
+				return scriptBody
+			},
+		},
+		{
+			"custom script",
+			script,
+			func(caller string, index int) string {
+				return fmt.Sprintf("package main\n\nfunc main() {\n\tprintln(%q, %d)\n}\n", caller, index)
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Get the runtime
+			r := GetRuntime(context.Background(), Run, Config{Script: testCase.script})
+
+			// Make sure there is no initialization logic
+			initialTxs, err := r.Initialize(
+				accounts[0],
+				func(_ *std.Tx) error {
+					return nil
+				},
+				estimateFn,
+				1_000_000,
+				common.DefaultGasPrice,
+			)
+			require.NoError(t, err)
+
+			assert.Nil(t, initialTxs)
+
+			// Construct the transactions
+			txs, err := r.ConstructTransactions(
+				accountKeys,
+				accounts,
+				transactions,
+				1_000_000,
+				common.DefaultGasPrice,
+				"dummy",
+				estimateFn,
+			)
+			require.NoError(t, err)
+
+			require.Len(t, txs, int(transactions))
+
+			// Make sure the scripts are executed by the callers
+			for index, tx := range txs {
+				assert.Equal(t, Run, TxType(tx))
+
+				vmMsg, ok := tx.Msgs[0].(vm.MsgRun)
+				require.True(t, ok)
+
+				require.NoError(t, vmMsg.ValidateBasic())
+
+				assert.Equal(t, scriptName, vmMsg.Package.Name)
+				assert.Empty(t, vmMsg.Package.Path)
+
+				require.Len(t, vmMsg.Package.Files, 1)
+				assert.Equal(t, testCase.body(vmMsg.Caller.String(), index), vmMsg.Package.Files[0].Body)
+			}
+		})
+	}
+}
+
+func TestRuntime_History(t *testing.T) {
+	t.Parallel()
+
+	var (
+		transactions = uint64(8)
+		accounts     = generateAccounts(4)
+		accountKeys  = testutils.GenerateAccounts(t, 4)
+
+		source    = testutils.GenerateAccounts(t, 2)
+		sender    = source[0].PubKey().Address()
+		recipient = source[1].PubKey().Address()
+
+		files = []*std.MemFile{
+			{
+				Name: "package.gno",
+				Body: "package history\n",
+			},
+		}
+
+		historyTxs = []HistoryTx{
+			{
+				Tx: &std.Tx{
+					Msgs: []std.Msg{
+						bank.MsgSend{
+							FromAddress: sender,
+							ToAddress:   recipient,
+							Amount:      std.NewCoins(std.NewCoin(common.Denomination, 100)),
+						},
+						// Multi-sends can't be replayed, and are dropped
+						bank.MsgMultiSend{},
+					},
+					Fee: std.Fee{GasWanted: 100_000},
+				},
+				Offset: time.Second,
+			},
+			{
+				Tx: &std.Tx{
+					Msgs: []std.Msg{
+						vm.MsgCall{
+							Caller:  sender,
+							PkgPath: "gno.land/r/demo/counter",
+							Func:    "Increment",
+							Args:    []string{"1"},
+						},
+					},
+					Fee: std.Fee{GasWanted: 200_000},
+				},
+				Offset: 3 * time.Second,
+			},
+			{
+				Tx: &std.Tx{
+					Msgs: []std.Msg{
+						bank.MsgMultiSend{},
+					},
+					Fee: std.Fee{GasWanted: 100_000},
+				},
+				Offset: 4 * time.Second,
+			},
+			{
+				Tx: &std.Tx{
+					Msgs: []std.Msg{
+						vm.MsgRun{
+							Caller: sender,
+							Package: &std.MemPackage{
+								Name:  scriptName,
+								Path:  "gno.land/e/" + sender.String() + "/run",
+								Files: files,
+							},
+						},
+					},
+					Fee: std.Fee{GasWanted: 300_000},
+				},
+				Offset: 5 * time.Second,
+			},
+			{
+				Tx: &std.Tx{
+					Msgs: []std.Msg{
+						vm.MsgAddPackage{
+							Creator: sender,
+							Package: &std.MemPackage{
+								Name:  "history",
+								Path:  "gno.land/p/demo/history",
+								Files: files,
+							},
+						},
+					},
+					// The source gas exceeds the block gas limit
+					Fee: std.Fee{GasWanted: 5_000_000},
+				},
+				Offset: 9 * time.Second,
+			},
+		}
+	)
+
+	for index, account := range accounts {
+		require.NoError(t, account.SetAddress(accountKeys[index].PubKey().Address()))
+	}
+
+	history := NewTxHistory(historyTxs)
+
+	// Make sure the unsupported messages were dropped
+	require.Equal(t, 4, history.Len())
+	assert.Equal(t, 2, history.Skipped())
+
+	// Make sure the schedule keeps the relative timing, compressed by the speed factor
+	assert.Equal(
+		t,
+		[]time.Duration{0, time.Second, 2 * time.Second, 4 * time.Second},
+		history.Schedule(2),
+	)
+
+	// Get the runtime
+	r := GetRuntime(context.Background(), History, Config{History: history})
+
+	// Make sure the costs cover the average source transaction
+	cost, err := r.CalculateRuntimeCosts(
+		accounts[0],
+		nil,
+		nil,
+		1_000_000,
+		common.DefaultGasPrice,
+		transactions,
+	)
+	require.NoError(t, err)
+
+	var (
+		totalFees = common.CalculateFeeInRatio(100_000, common.DefaultGasPrice).GasFee.Amount +
+			common.CalculateFeeInRatio(200_000, common.DefaultGasPrice).GasFee.Amount +
+			common.CalculateFeeInRatio(300_000, common.DefaultGasPrice).GasFee.Amount +
+			common.CalculateFeeInRatio(1_000_000, common.DefaultGasPrice).GasFee.Amount
+		average = (totalFees + 100 + 3) / 4
+	)
+
+	assert.Equal(t, int64(transactions)*average, cost.Amount)
+
+	// Construct the transactions
+	txs, err := r.ConstructTransactions(
+		accountKeys,
+		accounts,
+		transactions,
+		1_000_000,
+		common.DefaultGasPrice,
+		"dummy",
+		nil,
+	)
+	require.NoError(t, err)
+
+	require.Len(t, txs, int(transactions))
+
+	// Make sure the history is replayed in order, by the sub-accounts
+	for index, tx := range txs {
+		var (
+			creator = accounts[index%len(accounts)].GetAddress()
+			fee     = replayFee(history.txs[index%history.Len()].Tx.Fee, 1_000_000, common.DefaultGasPrice)
+		)
+
+		require.Len(t, tx.Msgs, 1)
+		assert.Equal(t, fee, tx.Fee)
+
+		switch msg := tx.Msgs[0].(type) {
+		case bank.MsgSend:
+			assert.Equal(t, creator, msg.FromAddress)
+			assert.Equal(t, recipient, msg.ToAddress)
+		case vm.MsgCall:
+			assert.Equal(t, creator, msg.Caller)
+			assert.Equal(t, "gno.land/r/demo/counter", msg.PkgPath)
+			assert.Equal(t, "Increment", msg.Func)
+			assert.Equal(t, []string{"1"}, msg.Args)
+		case vm.MsgRun:
+			assert.Equal(t, creator, msg.Caller)
+			assert.Empty(t, msg.Package.Path)
+		case vm.MsgAddPackage:
+			assert.Equal(t, creator, msg.Creator)
+			assert.Equal(t, PackageDeployment, TxType(tx))
+			assert.Contains(t, msg.Package.Path, fmt.Sprintf("%s/%s/history_", packagePathPrefix, creator))
+		default:
+			t.Fatalf("unexpected message type %T", msg)
+		}
+
+		require.NoError(t, tx.Msgs[0].ValidateBasic())
+	}
+
+	// Make sure the source history was left intact
+	assert.Equal(t, "gno.land/p/demo/history", history.txs[3].Tx.Msgs[0].(vm.MsgAddPackage).Package.Path)
+}
@@ -14,6 +14,11 @@ func init() {
 func SayHello(cur realm, name string) string {
 	return greeting + " " + name + "!"
 }
+
+// Render renders the greeting for the specified path
+func Render(path string) string {
+	return greeting + " " + path + "!"
+}
 `
 	packageBody = `package runtime
 
@@ -48,6 +53,64 @@ func GetGreeting(language Language) string {
 		return "Hello"
 	}
 }
+`
+	storageRealmBody = `package storage
+
+import (
+	"strconv"
+
+	"gno.land/p/nt/avl"
+)
+
+var (
+	values avl.Tree               // key -> value
+	writes = make(map[string]int) // key -> number of writes
+)
+
+// Set inserts the key, or updates its value
+func Set(cur realm, key, value string) {
+	values.Set(key, value)
+	writes[key]++
+}
+
+// Delete removes the key, if present
+func Delete(cur realm, key string) {
+	values.Remove(key)
+	delete(writes, key)
+}
+
+// Size returns the number of stored keys
+func Size() int {
+	return values.Size()
+}
+
+// Render renders the value of the key in the path,
+// or the number of stored keys for an empty path
+func Render(path string) string {
+	if path == "" {
+		return "keys: " + strconv.Itoa(values.Size())
+	}
+
+	value, exists := values.Get(path)
+	if !exists {
+		return "not found"
+	}
+
+	return value.(string)
+}
+`
+	scriptBody = `package main
+
+// main sums up the squares, so every run
+// is type-checked, compiled and executed
+func main() {
+	sum := 0
+	for i := 0; i < 100; i++ {
+		sum += i * i
+	}
+
+	println("sum:", sum)
+}
 `
 	gnomodBody = `
 module = "gno.land/r/demo/runtime"
@@ -57,7 +120,10 @@ gno = "0.9"
 
 const (
 	packageName     = "runtime"
+	scriptName      = "main"
 	realmFileName   = "realm.gno"
+	storageFileName = "storage.gno"
 	packageFileName = "package.gno"
+	scriptFileName  = "script.gno"
 	gnomodFileName  = "gnomod.toml"
 )
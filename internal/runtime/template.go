@@ -0,0 +1,334 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// templateSuffix is the suffix of the package files that are rendered
+// as templates. The files are deployed without the suffix
+const templateSuffix = ".tmpl"
+
+// randomCharset is the charset of the randomly generated strings
+const randomCharset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+var (
+	errInvalidRandRange  = errors.New("invalid random range")
+	errInvalidRandLength = errors.New("invalid random string length")
+	errEmptyPick         = errors.New("no values to pick from")
+
+	errInvalidScriptPackage = errors.New("scripts need to be in the main package")
+)
+
+// templateFuncs are the data generators available to the templates.
+// The generated values don't need to be cryptographically random
+//
+//nolint:gosec // Pseudo-random values are used
+var templateFuncs = template.FuncMap{
+	// randInt returns a random integer in [min, max]
+	"randInt": func(minValue, maxValue int) (int, error) {
+		if maxValue < minValue {
+			return 0, fmt.Errorf("%w, [%d, %d]", errInvalidRandRange, minValue, maxValue)
+		}
+
+		return minValue + rand.Intn(maxValue-minValue+1), nil
+	},
+	// randString returns a random alphanumeric string of the given length
+	"randString": func(length int) (string, error) {
+		if length < 0 {
+			return "", fmt.Errorf("%w, %d", errInvalidRandLength, length)
+		}
+
+		return randomString(length), nil
+	},
+	// pick returns one of the given values, at random
+	"pick": func(values ...any) (any, error) {
+		if len(values) == 0 {
+			return nil, errEmptyPick
+		}
+
+		return values[rand.Intn(len(values))], nil
+	},
+}
+
+// randomString returns a random alphanumeric string of the given length
+func randomString(length int) string {
+	b := make([]byte, length)
+	for i := range b {
+		//nolint:gosec // The generated strings don't need to be cryptographically random
+		b[i] = randomCharset[rand.Intn(len(randomCharset))]
+	}
+
+	return string(b)
+}
+
+// Template is a message data template (ex. a realm call argument),
+// rendered for each generated message (ex. Account-{{.Index}}).
+// Besides the message data, templates can use the
+// randInt, randString and pick data generators
+type Template struct {
+	tmpl *template.Template
+}
+
+// templateData is the per-message
+// data available to the templates
+type templateData struct {
+	Index   int    // the index of the message in the run
+	Address string // the address of the message creator
+	Random  int64  // a random non-negative value
+}
+
+// newTemplateData creates the template data for the
+// message with the given index, sent out by the given address
+func newTemplateData(address string, index int) templateData {
+	return templateData{
+		Index:   index,
+		Address: address,
+		//nolint:gosec // The template values don't need to be cryptographically random
+		Random: rand.Int63(),
+	}
+}
+
+// ParseTemplate parses the message data template
+func ParseTemplate(text string) (*Template, error) {
+	tmpl, err := template.New("").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse template %q, %w", text, err)
+	}
+
+	// The template is executed once, so invalid field
+	// references and generator arguments are caught before the run
+	if err := tmpl.Execute(io.Discard, templateData{}); err != nil {
+		return nil, fmt.Errorf("unable to execute template %q, %w", text, err)
+	}
+
+	return &Template{
+		tmpl: tmpl,
+	}, nil
+}
+
+// ParseTemplates parses the message data templates
+func ParseTemplates(texts []string) ([]*Template, error) {
+	templates := make([]*Template, 0, len(texts))
+
+	for _, text := range texts {
+		tmpl, err := ParseTemplate(text)
+		if err != nil {
+			return nil, err
+		}
+
+		templates = append(templates, tmpl)
+	}
+
+	return templates, nil
+}
+
+// mustParseTemplate parses the built-in message data template
+func mustParseTemplate(text string) *Template {
+	tmpl, err := ParseTemplate(text)
+	if err != nil {
+		panic(err)
+	}
+
+	return tmpl
+}
+
+// render renders the template using the given message data
+func (t *Template) render(data templateData) (string, error) {
+	var b strings.Builder
+
+	// The template is verified on parse, but generator arguments
+	// can depend on the message data (ex. {{randInt .Index 10}}),
+	// so the execution can still fail for some messages
+	if err := t.tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("unable to execute template, %w", err)
+	}
+
+	return b.String(), nil
+}
+
+// Render renders the template for the message
+// with the given index, sent out by the given address
+func (t *Template) Render(address string, index int) (string, error) {
+	return t.render(newTemplateData(address, index))
+}
+
+// renderTemplates renders the templates for the message
+// with the given index, sent out by the given address
+func renderTemplates(templates []*Template, address string, index int) ([]string, error) {
+	var (
+		data     = newTemplateData(address, index)
+		rendered = make([]string, 0, len(templates))
+	)
+
+	for _, tmpl := range templates {
+		value, err := tmpl.render(data)
+		if err != nil {
+			return nil, err
+		}
+
+		rendered = append(rendered, value)
+	}
+
+	return rendered, nil
+}
+
+// PackageTemplate is a package, whose template files (with the .tmpl suffix)
+// are rendered for each message. Packages without template files are static
+type PackageTemplate struct {
+	files []packageFile // sorted by the deployed file name
+}
+
+// packageFile is a single package file
+type packageFile struct {
+	name string
+	body string
+	tmpl *Template // the body template, if any
+}
+
+// ParsePackageTemplate parses the package files.
+// Packages without a gnomod.toml get the built-in one
+func ParsePackageTemplate(files []*std.MemFile) (*PackageTemplate, error) {
+	pkgFiles, err := parsePackageFiles(files)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hasPackageFile(pkgFiles, gnomodFileName) {
+		pkgFiles = append(pkgFiles, packageFile{
+			name: gnomodFileName,
+			body: gnomodBody,
+		})
+	}
+
+	return newPackageTemplate(pkgFiles), nil
+}
+
+// ParseScriptTemplate parses the files of the executed main package.
+// Unlike deployed packages, scripts don't need a gnomod.toml
+func ParseScriptTemplate(files []*std.MemFile) (*PackageTemplate, error) {
+	pkgFiles, err := parsePackageFiles(files)
+	if err != nil {
+		return nil, err
+	}
+
+	script := newPackageTemplate(pkgFiles)
+
+	rendered, err := script.render("", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := filesPackageName(rendered); name != scriptName {
+		return nil, fmt.Errorf("%w, found package %s", errInvalidScriptPackage, name)
+	}
+
+	return script, nil
+}
+
+// parsePackageFiles parses the package files, and their templates
+func parsePackageFiles(files []*std.MemFile) ([]packageFile, error) {
+	pkgFiles := make([]packageFile, 0, len(files)+1)
+
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name, templateSuffix) {
+			pkgFiles = append(pkgFiles, packageFile{
+				name: file.Name,
+				body: file.Body,
+			})
+
+			continue
+		}
+
+		tmpl, err := ParseTemplate(file.Body)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse package file %s, %w", file.Name, err)
+		}
+
+		pkgFiles = append(pkgFiles, packageFile{
+			name: strings.TrimSuffix(file.Name, templateSuffix),
+			tmpl: tmpl,
+		})
+	}
+
+	return pkgFiles, nil
+}
+
+// newPackageTemplate creates the package template from the parsed files
+func newPackageTemplate(files []packageFile) *PackageTemplate {
+	// The package files need to be sorted by name
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].name < files[j].name
+	})
+
+	return &PackageTemplate{
+		files: files,
+	}
+}
+
+// mustParsePackageTemplate parses the built-in package files
+func mustParsePackageTemplate(files []*std.MemFile) *PackageTemplate {
+	pkg, err := ParsePackageTemplate(files)
+	if err != nil {
+		panic(err)
+	}
+
+	return pkg
+}
+
+// mustParseScriptTemplate parses the built-in script files
+func mustParseScriptTemplate(files []*std.MemFile) *PackageTemplate {
+	script, err := ParseScriptTemplate(files)
+	if err != nil {
+		panic(err)
+	}
+
+	return script
+}
+
+// hasPackageFile checks if the file with the given name is present
+func hasPackageFile(files []packageFile, name string) bool {
+	for _, file := range files {
+		if file.name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// render renders the package files for the message
+// with the given index, sent out by the given address
+func (p *PackageTemplate) render(address string, index int) ([]*std.MemFile, error) {
+	var (
+		data  = newTemplateData(address, index)
+		files = make([]*std.MemFile, 0, len(p.files))
+	)
+
+	for _, file := range p.files {
+		body := file.body
+
+		if file.tmpl != nil {
+			rendered, err := file.tmpl.render(data)
+			if err != nil {
+				return nil, fmt.Errorf("unable to render package file %s, %w", file.name, err)
+			}
+
+			body = rendered
+		}
+
+		files = append(files, &std.MemFile{
+			Name: file.name,
+			Body: body,
+		})
+	}
+
+	return files, nil
+}
@@ -0,0 +1,226 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplate_Render(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name     string
+		text     string
+		expected string
+		valid    bool
+	}{
+		{
+			"static text",
+			"hello",
+			"hello",
+			true,
+		},
+		{
+			"message index",
+			"Account-{{.Index}}",
+			"Account-5",
+			true,
+		},
+		{
+			"creator address",
+			"{{.Address}}:{{.Index}}",
+			"g1dummy:5",
+			true,
+		},
+		{
+			"random integer",
+			"{{randInt 7 7}}",
+			"7",
+			true,
+		},
+		{
+			"picked value",
+			`{{pick "a" "a"}}`,
+			"a",
+			true,
+		},
+		{
+			"invalid random range",
+			"{{randInt 10 1}}",
+			"",
+			false,
+		},
+		{
+			"empty pick",
+			"{{pick}}",
+			"",
+			false,
+		},
+		{
+			"unknown function",
+			"{{randFloat}}",
+			"",
+			false,
+		},
+		{
+			"invalid syntax",
+			"{{.Index",
+			"",
+			false,
+		},
+		{
+			"unknown field",
+			"{{.Unknown}}",
+			"",
+			false,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpl, err := ParseTemplate(testCase.text)
+			if !testCase.valid {
+				assert.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			rendered, err := renderTemplates([]*Template{tmpl}, "g1dummy", 5)
+			require.NoError(t, err)
+
+			assert.Equal(t, []string{testCase.expected}, rendered)
+		})
+	}
+}
+
+func TestTemplate_RenderError(t *testing.T) {
+	t.Parallel()
+
+	// The generator arguments depend on the message index,
+	// so the template passes the verification on parse
+	tmpl, err := ParseTemplate("{{randInt .Index 3}}")
+	require.NoError(t, err)
+
+	t.Run("template", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := renderTemplates([]*Template{tmpl}, "g1dummy", 5)
+		assert.ErrorIs(t, err, errInvalidRandRange)
+	})
+
+	t.Run("package", func(t *testing.T) {
+		t.Parallel()
+
+		pkg, err := ParsePackageTemplate([]*std.MemFile{
+			{
+				Name: "main.gno.tmpl",
+				Body: "package b\n\nvar index = {{randInt .Index 3}}\n",
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = pkg.render("g1dummy", 5)
+		assert.ErrorIs(t, err, errInvalidRandRange)
+	})
+}
+
+func TestTemplate_Generators(t *testing.T) {
+	t.Parallel()
+
+	// Make sure the random integers are in range
+	randInt, err := ParseTemplate("{{randInt 1 3}}")
+	require.NoError(t, err)
+
+	// Make sure the random strings have the given length
+	randString, err := ParseTemplate("{{randString 256}}")
+	require.NoError(t, err)
+
+	// Make sure the values are picked from the given ones
+	pick, err := ParseTemplate(`{{pick "a" "b"}}`)
+	require.NoError(t, err)
+
+	for index := 0; index < 100; index++ {
+		rendered, err := renderTemplates([]*Template{randInt, randString, pick}, "g1dummy", index)
+		require.NoError(t, err)
+
+		assert.Contains(t, []string{"1", "2", "3"}, rendered[0])
+		assert.Len(t, rendered[1], 256)
+		assert.Contains(t, []string{"a", "b"}, rendered[2])
+	}
+}
+
+func TestTemplate_Package(t *testing.T) {
+	t.Parallel()
+
+	pkg, err := ParsePackageTemplate([]*std.MemFile{
+		{
+			Name: "main.gno.tmpl",
+			Body: "package b\n\nvar index = {{.Index}}\n",
+		},
+		{
+			Name: "a.gno",
+			Body: "package b\n\nvar raw = \"{{.Index}}\"\n",
+		},
+	})
+	require.NoError(t, err)
+
+	files, err := pkg.render("g1dummy", 5)
+	require.NoError(t, err)
+
+	// Make sure the built-in gnomod.toml is added,
+	// and the files are sorted by the deployed name
+	require.Len(t, files, 3)
+
+	assert.Equal(t, "a.gno", files[0].Name)
+	assert.Equal(t, gnomodFileName, files[1].Name)
+	assert.Equal(t, "main.gno", files[2].Name)
+
+	// Make sure only the template files are rendered
+	assert.Equal(t, "package b\n\nvar raw = \"{{.Index}}\"\n", files[0].Body)
+	assert.Equal(t, "package b\n\nvar index = 5\n", files[2].Body)
+}
+
+func TestTemplate_Script(t *testing.T) {
+	t.Parallel()
+
+	t.Run("main package", func(t *testing.T) {
+		t.Parallel()
+
+		script, err := ParseScriptTemplate([]*std.MemFile{
+			{
+				Name: "script.gno.tmpl",
+				Body: "package main\n\nfunc main() {\n\tprintln({{.Index}})\n}\n",
+			},
+		})
+		require.NoError(t, err)
+
+		files, err := script.render("g1dummy", 3)
+		require.NoError(t, err)
+
+		// Make sure no gnomod.toml is added
+		require.Len(t, files, 1)
+
+		assert.Equal(t, "script.gno", files[0].Name)
+		assert.Contains(t, files[0].Body, "println(3)")
+	})
+
+	t.Run("non-main package", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseScriptTemplate([]*std.MemFile{
+			{
+				Name: "script.gno",
+				Body: "package runtime\n\nfunc main() {}\n",
+			},
+		})
+
+		assert.ErrorIs(t, err, errInvalidScriptPackage)
+	})
+}
@@ -1,11 +1,24 @@
 package runtime
 
+import (
+	"strings"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
 type Type string
 
 const (
 	RealmDeployment   Type = "REALM_DEPLOYMENT"
 	PackageDeployment Type = "PACKAGE_DEPLOYMENT"
 	RealmCall         Type = "REALM_CALL"
+	RealmStorage      Type = "REALM_STORAGE"
+	BankSend          Type = "BANK_SEND"
+	Run               Type = "RUN"
+	Mixed             Type = "MIXED"
+	History           Type = "HISTORY"
 	unknown           Type = "UNKNOWN"
 )
 
@@ -13,8 +26,13 @@ const (
 // is a supported runtime type
 func IsRuntime(runtime Type) bool {
 	return runtime == RealmCall ||
+		runtime == RealmStorage ||
 		runtime == RealmDeployment ||
-		runtime == PackageDeployment
+		runtime == PackageDeployment ||
+		runtime == BankSend ||
+		runtime == Run ||
+		runtime == Mixed ||
+		runtime == History
 }
 
 // String returns a string representation
@@ -27,7 +45,46 @@ func (r Type) String() string {
 		return string(PackageDeployment)
 	case RealmCall:
 		return string(RealmCall)
+	case RealmStorage:
+		return string(RealmStorage)
+	case BankSend:
+		return string(BankSend)
+	case Run:
+		return string(Run)
+	case Mixed:
+		return string(Mixed)
+	case History:
+		return string(History)
 	default:
 		return string(unknown)
 	}
 }
+
+// TxType returns the type of the runtime
+// the transaction belongs to, based on its first message
+func TxType(tx *std.Tx) Type {
+	if len(tx.Msgs) == 0 {
+		return unknown
+	}
+
+	switch msg := tx.Msgs[0].(type) {
+	case bank.MsgSend:
+		return BankSend
+	case vm.MsgCall:
+		if isStorageRealm(msg.PkgPath) {
+			return RealmStorage
+		}
+
+		return RealmCall
+	case vm.MsgRun:
+		return Run
+	case vm.MsgAddPackage:
+		if msg.Package != nil && strings.HasPrefix(msg.Package.Path, packagePathPrefix) {
+			return PackageDeployment
+		}
+
+		return RealmDeployment
+	default:
+		return unknown
+	}
+}
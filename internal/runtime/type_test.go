@@ -29,6 +29,31 @@ func TestType_IsRuntime(t *testing.T) {
 			RealmCall,
 			true,
 		},
+		{
+			"Realm Storage",
+			RealmStorage,
+			true,
+		},
+		{
+			"Bank Send",
+			BankSend,
+			true,
+		},
+		{
+			"Run",
+			Run,
+			true,
+		},
+		{
+			"Mixed",
+			Mixed,
+			true,
+		},
+		{
+			"History",
+			History,
+			true,
+		},
 		{
 			"Dummy mode",
 			Type("Dummy mode"),
@@ -68,6 +93,31 @@ func TestType_String(t *testing.T) {
 			RealmCall,
 			string(RealmCall),
 		},
+		{
+			"Realm Storage",
+			RealmStorage,
+			string(RealmStorage),
+		},
+		{
+			"Bank Send",
+			BankSend,
+			string(BankSend),
+		},
+		{
+			"Run",
+			Run,
+			string(Run),
+		},
+		{
+			"Mixed",
+			Mixed,
+			string(Mixed),
+		},
+		{
+			"History",
+			History,
+			string(History),
+		},
 		{
 			"Dummy mode",
 			Type("Dummy mode"),
@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// scenarioFile is the declarative scenario file format.
+// It mirrors the run configuration, with the endpoints and
+// the key source specified in a more readable form
+type scenarioFile struct {
+	Config `yaml:",inline"`
+
+	Endpoints    []string `yaml:"endpoints"`    // the cluster endpoints, the first one being the primary
+	MnemonicFile string   `yaml:"mnemonicFile"` // the path to the file containing the mnemonic, if any
+}
+
+// LoadScenario loads the scenario file at the given path into the configuration.
+// Only the values present in the scenario file are overwritten.
+// Relative paths in the scenario file are resolved against the scenario file directory
+func LoadScenario(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to read scenario file, %w", err)
+	}
+
+	file := scenarioFile{
+		Config: *cfg,
+	}
+
+	// Unknown fields are not allowed, so typos
+	// in the scenario file don't go unnoticed
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(&file); err != nil {
+		return fmt.Errorf("unable to parse scenario file, %w", err)
+	}
+
+	// Note down the keys set in each stage, so stage
+	// values explicitly set to zero are not inherited
+	var stageKeys struct {
+		Stages []map[string]any `yaml:"stages"`
+	}
+
+	if err := yaml.Unmarshal(data, &stageKeys); err != nil {
+		return fmt.Errorf("unable to parse scenario stages, %w", err)
+	}
+
+	for index, keys := range stageKeys.Stages {
+		if index >= len(file.Stages) {
+			break
+		}
+
+		file.Stages[index].set = make(map[string]bool, len(keys))
+
+		for key := range keys {
+			file.Stages[index].set[key] = true
+		}
+	}
+
+	dir := filepath.Dir(path)
+
+	if len(file.Endpoints) > 0 {
+		file.URL = strings.Join(file.Endpoints, ",")
+	}
+
+	if file.MnemonicFile != "" {
+		mnemonic, err := os.ReadFile(resolvePath(dir, file.MnemonicFile))
+		if err != nil {
+			return fmt.Errorf("unable to read mnemonic file, %w", err)
+		}
+
+		file.Mnemonic = strings.TrimSpace(string(mnemonic))
+	}
+
+	file.Realm = resolvePath(dir, file.Realm)
+	file.Script = resolvePath(dir, file.Script)
+	file.HistoryFile = resolvePath(dir, file.HistoryFile)
+
+	for index := range file.Stages {
+		file.Stages[index].Realm = resolvePath(dir, file.Stages[index].Realm)
+		file.Stages[index].Script = resolvePath(dir, file.Stages[index].Script)
+		file.Stages[index].HistoryFile = resolvePath(dir, file.Stages[index].HistoryFile)
+	}
+
+	*cfg = file.Config
+
+	return nil
+}
+
+// resolvePath resolves the relative path against the given directory
+func resolvePath(dir, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(dir, path)
+}
@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScenario_StageInheritance(t *testing.T) {
+	t.Parallel()
+
+	var (
+		path     = filepath.Join(t.TempDir(), "scenario.yaml")
+		scenario = `
+mode: REALM_STORAGE
+transferAmount: 5
+storageDeletes: 20
+storagePrice: 100
+queryConcurrency: 4
+queryRate: 100
+
+stages:
+  - name: explicit
+    transferAmount: 0
+    storageDeletes: 0
+    storagePrice: 0
+    queryConcurrency: 0
+    queryRate: 0
+  - name: inherited
+  - name: overridden
+    storageDeletes: 50
+`
+	)
+
+	require.NoError(t, os.WriteFile(path, []byte(scenario), 0o600))
+
+	cfg := &Config{}
+	require.NoError(t, LoadScenario(path, cfg))
+
+	stages := cfg.stages()
+	require.Len(t, stages, 3)
+
+	// Make sure the values explicitly set to zero are not inherited
+	assert.Zero(t, stages[0].TransferAmount)
+	assert.Zero(t, stages[0].StorageDeletes)
+	assert.Zero(t, stages[0].StoragePrice)
+	assert.Zero(t, stages[0].QueryConcurrency)
+	assert.Zero(t, stages[0].QueryRate)
+
+	// Make sure the missing values are inherited
+	assert.Equal(t, uint64(5), stages[1].TransferAmount)
+	assert.Equal(t, uint64(20), stages[1].StorageDeletes)
+	assert.Equal(t, uint64(100), stages[1].StoragePrice)
+	assert.Equal(t, uint64(4), stages[1].QueryConcurrency)
+	assert.Equal(t, uint64(100), stages[1].QueryRate)
+
+	// Make sure the set values are kept
+	assert.Equal(t, uint64(50), stages[2].StorageDeletes)
+	assert.Equal(t, uint64(5), stages[2].TransferAmount)
+}
@@ -0,0 +1,175 @@
+package txfile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+var (
+	errMissingMetadata = errors.New("missing transaction file metadata")
+	errTxCountMismatch = errors.New("transaction count does not match the metadata")
+)
+
+// Metadata is the metadata of the exported transactions
+type Metadata struct {
+	ChainID      string    `json:"chainID"`      // the chain ID the transactions are signed for
+	Mode         string    `json:"mode"`         // the runtime mode of the transactions
+	CreatedAt    time.Time `json:"createdAt"`    // the time the transactions were exported
+	Transactions int       `json:"transactions"` // the number of exported transactions
+
+	// The load of rate-controlled runs, if any
+	Rate     uint64        `json:"rate,omitempty"`     // the target send rate (txs / s)
+	Duration time.Duration `json:"duration,omitempty"` // the duration of a time-bounded run
+	Profile  string        `json:"profile,omitempty"`  // the load profile specification
+
+	// The send offset of each transaction, relative to the run start, if scheduled
+	Schedule []time.Duration `json:"schedule,omitempty"`
+
+	Accounts []Account `json:"accounts"` // the accounts that signed the transactions
+}
+
+// Account is an account that signed the exported transactions,
+// as it was at the time the transactions were signed
+type Account struct {
+	Address       string `json:"address"`
+	AccountNumber uint64 `json:"accountNumber"`
+	Sequence      uint64 `json:"sequence"` // the sequence of the first transaction the account signed
+}
+
+// Write writes the signed transactions to the file at the given path,
+// as JSON lines. The first line holds the metadata, and every
+// following line holds a single (amino JSON) transaction
+func Write(path string, metadata *Metadata, txs []*std.Tx) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("unable to create file, %w", err)
+	}
+
+	// The file is closed explicitly once written,
+	// so the deferred close only covers the early returns
+	defer func() {
+		_ = f.Close()
+	}()
+
+	w := bufio.NewWriter(f)
+
+	metadata.Transactions = len(txs)
+
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("unable to marshal metadata, %w", err)
+	}
+
+	if err := writeLine(w, metadataJSON); err != nil {
+		return err
+	}
+
+	for _, tx := range txs {
+		txJSON, err := amino.MarshalJSON(tx)
+		if err != nil {
+			return fmt.Errorf("unable to marshal transaction, %w", err)
+		}
+
+		if err := writeLine(w, txJSON); err != nil {
+			return err
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("unable to write to file, %w", err)
+	}
+
+	// The written data might only be persisted on close
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("unable to close file, %w", err)
+	}
+
+	return nil
+}
+
+// writeLine writes a single line to the writer
+func writeLine(w io.Writer, line []byte) error {
+	if _, err := w.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("unable to write to file, %w", err)
+	}
+
+	return nil
+}
+
+// Read reads the metadata and the signed transactions
+// from the file at the given path
+func Read(path string) (*Metadata, []*std.Tx, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to open file, %w", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	// Transactions can be large (ex. package deployments),
+	// so the lines are not limited in size
+	var (
+		r        = bufio.NewReader(f)
+		metadata *Metadata
+		txs      = make([]*std.Tx, 0)
+	)
+
+	for {
+		line, readErr := r.ReadBytes('\n')
+		if readErr != nil && !errors.Is(readErr, io.EOF) {
+			return nil, nil, fmt.Errorf("unable to read file, %w", readErr)
+		}
+
+		line = bytes.TrimSpace(line)
+
+		switch {
+		case len(line) == 0:
+			// Empty lines are skipped
+		case metadata == nil:
+			// The first line holds the metadata
+			metadata = &Metadata{}
+
+			if err := json.Unmarshal(line, metadata); err != nil {
+				return nil, nil, fmt.Errorf("unable to unmarshal metadata, %w", err)
+			}
+		default:
+			tx := &std.Tx{}
+
+			if err := amino.UnmarshalJSON(line, tx); err != nil {
+				return nil, nil, fmt.Errorf("unable to unmarshal transaction %d, %w", len(txs), err)
+			}
+
+			txs = append(txs, tx)
+		}
+
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+	}
+
+	if metadata == nil {
+		return nil, nil, errMissingMetadata
+	}
+
+	if metadata.Transactions != len(txs) {
+		return nil, nil, fmt.Errorf(
+			"%w, expected %d, got %d",
+			errTxCountMismatch,
+			metadata.Transactions,
+			len(txs),
+		)
+	}
+
+	return metadata, txs, nil
+}
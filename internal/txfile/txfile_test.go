@@ -0,0 +1,129 @@
+package txfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/supernova/internal/common"
+	"github.com/gnolang/supernova/internal/signer"
+	testutils "github.com/gnolang/supernova/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxFile_WriteRead(t *testing.T) {
+	t.Parallel()
+
+	var (
+		accounts = testutils.GenerateAccounts(t, 2)
+		sender   = accounts[0].PubKey().Address()
+
+		path = filepath.Join(t.TempDir(), "txs.jsonl")
+
+		metadata = &Metadata{
+			ChainID:   "dev",
+			Mode:      "MIXED",
+			CreatedAt: time.Now().UTC().Truncate(time.Second),
+			Rate:      10,
+			Duration:  time.Minute,
+			Schedule:  []time.Duration{0, time.Second},
+			Accounts: []Account{
+				{
+					Address:       sender.String(),
+					AccountNumber: 5,
+					Sequence:      10,
+				},
+			},
+		}
+
+		txs = []*std.Tx{
+			{
+				Msgs: []std.Msg{
+					bank.MsgSend{
+						FromAddress: sender,
+						ToAddress:   accounts[1].PubKey().Address(),
+						Amount:      std.NewCoins(std.NewCoin(common.Denomination, 10)),
+					},
+				},
+				Fee: common.CalculateFeeInRatio(100_000, common.DefaultGasPrice),
+			},
+			{
+				Msgs: []std.Msg{
+					vm.MsgCall{
+						Caller:  sender,
+						PkgPath: "gno.land/r/demo/test",
+						Func:    "SayHello",
+						Args:    []string{"multi\nline"},
+					},
+				},
+				Fee: common.CalculateFeeInRatio(200_000, common.DefaultGasPrice),
+			},
+		}
+	)
+
+	for index, tx := range txs {
+		cfg := signer.SignCfg{
+			ChainID:       metadata.ChainID,
+			AccountNumber: 5,
+			Sequence:      10 + uint64(index),
+		}
+
+		require.NoError(t, signer.SignTx(tx, accounts[0], cfg))
+	}
+
+	require.NoError(t, Write(path, metadata, txs))
+
+	readMetadata, readTxs, err := Read(path)
+	require.NoError(t, err)
+
+	// Make sure the metadata matches
+	assert.Equal(t, len(txs), readMetadata.Transactions)
+	assert.Equal(t, metadata, readMetadata)
+
+	// Make sure the transactions match, including the signatures
+	require.Len(t, readTxs, len(txs))
+
+	for index, tx := range txs {
+		assert.Equal(t, tx.Msgs, readTxs[index].Msgs)
+		assert.Equal(t, tx.Fee, readTxs[index].Fee)
+		assert.Equal(t, tx.Signatures, readTxs[index].Signatures)
+	}
+}
+
+func TestTxFile_Read(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name        string
+		contents    string
+		expectedErr error
+	}{
+		{
+			"empty file",
+			"",
+			errMissingMetadata,
+		},
+		{
+			"missing transactions",
+			`{"chainID":"dev","transactions":2,"accounts":[]}` + "\n",
+			errTxCountMismatch,
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "txs.jsonl")
+			require.NoError(t, os.WriteFile(path, []byte(testCase.contents), 0o600))
+
+			_, _, err := Read(path)
+			assert.ErrorIs(t, err, testCase.expectedErr)
+		})
+	}
+}